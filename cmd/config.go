@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/tchssk/ago/convert"
+)
+
+// configFileName is the name of the config file, which is looked up from the
+// current directory upward and then in the home directory.
+const configFileName = ".ago.yaml"
+
+// config is the schema of the config file. Each setting is overridden by the
+// environment variable named after its key, e.g. AGO_TARGET or
+// AGO_OUTPUT_LAYOUT. Lists are comma-separated in the environment.
+type config struct {
+	// Target is the version of goa DSL to generate, v1 or v3.
+	Target string `mapstructure:"target"`
+	// Templates is the directory of the templates that replace the built-in
+	// ones.
+	Templates string `mapstructure:"templates"`
+	Output    struct {
+		// Layout is file or package.
+		Layout string `mapstructure:"layout"`
+		// Path is the file or the directory of the package the design is
+		// written to. The file layout writes to stdout if it is empty.
+		Path string `mapstructure:"path"`
+	} `mapstructure:"output"`
+	// Types renames the types of definitions. It is a list because viper
+	// lowercases the keys of maps.
+	Types []typeMapping `mapstructure:"types"`
+	// Formats maps custom formats, or overrides the mapping of formats, to
+	// goa types and validations. It is a list for the same reason.
+	Formats []formatMapping `mapstructure:"formats"`
+	// Grouping is the strategy to group operations into resources, path,
+	// tag or extension.
+	Grouping string `mapstructure:"grouping"`
+	Ignore   struct {
+		// Paths and Tags are the patterns of the paths and of the tags of
+		// the operations that are not converted.
+		Paths []string `mapstructure:"paths"`
+		Tags  []string `mapstructure:"tags"`
+	} `mapstructure:"ignore"`
+	// Security is the name of the security scheme of the API when the
+	// definition has no security requirements.
+	Security string `mapstructure:"security"`
+	Naming   struct {
+		MediaTypeSuffix string `mapstructure:"media-type-suffix"`
+		PayloadSuffix   string `mapstructure:"payload-suffix"`
+	} `mapstructure:"naming"`
+}
+
+// typeMapping renames the type of a definition.
+type typeMapping struct {
+	Definition string `mapstructure:"definition"`
+	Type       string `mapstructure:"type"`
+}
+
+// formatMapping maps a format to a goa type and a format validation.
+type formatMapping struct {
+	Format     string `mapstructure:"format"`
+	Type       string `mapstructure:"type"`
+	Validation string `mapstructure:"validation"`
+}
+
+// configKeys are the keys of the settings that can be overridden by the
+// environment.
+var configKeys = []string{
+	"target",
+	"templates",
+	"output.layout",
+	"output.path",
+	"grouping",
+	"ignore.paths",
+	"ignore.tags",
+	"security",
+	"naming.media-type-suffix",
+	"naming.payload-suffix",
+}
+
+// configEnvReplacer turns keys into the names of environment variables.
+var configEnvReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// findConfigFile returns the config file of the current directory or of the
+// closest parent directory.
+func findConfigFile() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, configFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// loadConfig returns the settings read by viper. Relative paths of the config
+// file are relative to the directory of the file.
+func loadConfig() (*config, error) {
+	var cfg config
+	if err := viper.UnmarshalExact(&cfg); err != nil {
+		return nil, err
+	}
+	if file := viper.ConfigFileUsed(); file != "" {
+		dir := filepath.Dir(file)
+		cfg.Templates = configPath(dir, "templates", cfg.Templates)
+		cfg.Output.Path = configPath(dir, "output.path", cfg.Output.Path)
+	}
+	return &cfg, nil
+}
+
+// configPath returns path, the setting at key, relative to dir if it comes
+// from the config file.
+func configPath(dir, key, path string) string {
+	if path == "" || filepath.IsAbs(path) || !viper.InConfig(key) {
+		return path
+	}
+	if _, ok := os.LookupEnv("AGO_" + strings.ToUpper(configEnvReplacer.Replace(key))); ok {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// options returns the options of the conversion set by cfg.
+func (cfg *config) options() convert.Options {
+	var typeNames map[string]string
+	if len(cfg.Types) > 0 {
+		typeNames = make(map[string]string)
+		for _, m := range cfg.Types {
+			typeNames[m.Definition] = m.Type
+		}
+	}
+	var formats map[string]convert.FormatMapping
+	if len(cfg.Formats) > 0 {
+		formats = make(map[string]convert.FormatMapping)
+		for _, m := range cfg.Formats {
+			formats[m.Format] = convert.FormatMapping{
+				Type:       m.Type,
+				Validation: m.Validation,
+			}
+		}
+	}
+	return convert.Options{
+		Naming: convert.Naming{
+			MediaTypeSuffix: cfg.Naming.MediaTypeSuffix,
+			PayloadSuffix:   cfg.Naming.PayloadSuffix,
+		},
+		TypeNames:       typeNames,
+		Formats:         formats,
+		Grouping:        cfg.Grouping,
+		IgnorePaths:     cfg.Ignore.Paths,
+		IgnoreTags:      cfg.Ignore.Tags,
+		DefaultSecurity: cfg.Security,
+		Target:          cfg.Target,
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/tchssk/ago/convert"
+)
+
+func TestFindConfigFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("TempDir returned %s", err)
+	}
+	defer os.RemoveAll(root)
+	root, _ = filepath.EvalSymlinks(root)
+	dir := filepath.Join(root, "api", "v1")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll returned %s", err)
+	}
+	expected := filepath.Join(root, configFileName)
+	if err := ioutil.WriteFile(expected, nil, 0644); err != nil {
+		t.Fatalf("WriteFile returned %s", err)
+	}
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir returned %s", err)
+	}
+	if actual, ok := findConfigFile(); !ok || actual != expected {
+		t.Errorf("got %s, expected %s", actual, expected)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("TempDir returned %s", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, configFileName)
+	content := `target: v3
+templates: templates
+output:
+  layout: package
+  path: design
+types:
+- {definition: Pet, type: Animal}
+formats:
+- {format: email-address, type: String, validation: email}
+grouping: tag
+ignore:
+  tags: [internal]
+naming:
+  payload-suffix: Body
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile returned %s", err)
+	}
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(file)
+	viper.SetEnvPrefix("ago")
+	viper.SetEnvKeyReplacer(configEnvReplacer)
+	for _, key := range configKeys {
+		viper.BindEnv(key)
+	}
+	os.Setenv("AGO_OUTPUT_PATH", "out")
+	defer os.Unsetenv("AGO_OUTPUT_PATH")
+	os.Setenv("AGO_IGNORE_PATHS", "/internal/*,/debug")
+	defer os.Unsetenv("AGO_IGNORE_PATHS")
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig returned %s", err)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig returned %s", err)
+	}
+	if cfg.Target != "v3" || cfg.Output.Layout != "package" || cfg.Naming.PayloadSuffix != "Body" {
+		t.Errorf("unexpected settings %+v", cfg)
+	}
+	if expected := filepath.Join(dir, "templates"); cfg.Templates != expected {
+		t.Errorf("got templates %s, expected %s", cfg.Templates, expected)
+	}
+	if cfg.Output.Path != "out" {
+		t.Errorf("got output path %s, expected out", cfg.Output.Path)
+	}
+	if !reflect.DeepEqual(cfg.Ignore.Paths, []string{"/internal/*", "/debug"}) || !reflect.DeepEqual(cfg.Ignore.Tags, []string{"internal"}) {
+		t.Errorf("unexpected ignored paths %v and tags %v", cfg.Ignore.Paths, cfg.Ignore.Tags)
+	}
+	if grouping := cfg.options().Grouping; grouping != "tag" {
+		t.Errorf("got grouping %s, expected tag", grouping)
+	}
+	if typeNames := cfg.options().TypeNames; !reflect.DeepEqual(typeNames, map[string]string{"Pet": "Animal"}) {
+		t.Errorf("unexpected types %v", typeNames)
+	}
+	if formats := cfg.options().Formats; !reflect.DeepEqual(formats, map[string]convert.FormatMapping{"email-address": {Type: "String", Validation: "email"}}) {
+		t.Errorf("unexpected formats %v", formats)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("unknown: true\n"), 0644); err != nil {
+		t.Fatalf("WriteFile returned %s", err)
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig returned %s", err)
+	}
+	if _, err := loadConfig(); err == nil {
+		t.Errorf("loadConfig accepted an unknown setting")
+	}
+}
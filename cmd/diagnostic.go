@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tchssk/ago/convert"
+)
+
+// Formats of diagnostics.
+const (
+	diagnosticsText = "text"
+	diagnosticsJSON = "json"
+)
+
+// errorDiagnostic returns the diagnostic of err.
+func errorDiagnostic(err error) convert.Diagnostic {
+	switch e := err.(type) {
+	case *convert.Error:
+		return convert.Diagnostic{Severity: convert.SeverityError, Pointer: e.Pointer, Message: e.Message}
+	case *convert.SyntaxError:
+		return convert.Diagnostic{Severity: convert.SeverityError, Pointer: e.Pointer, Message: e.Error()}
+	}
+	return convert.Diagnostic{Severity: convert.SeverityError, Message: err.Error()}
+}
+
+// diagnostics is a list of diagnostics in the order they are found.
+type diagnostics []convert.Diagnostic
+
+// hasErrors returns true if ds contains errors.
+func (ds diagnostics) hasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == convert.SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// strict turns the warnings of ds into errors.
+func (ds diagnostics) strict() {
+	for i := range ds {
+		ds[i].Severity = convert.SeverityError
+	}
+}
+
+// write writes ds to w in format. JSON is always written so that it can be
+// parsed, text only if there are diagnostics.
+func (ds diagnostics) write(w io.Writer, format string) error {
+	switch format {
+	case diagnosticsText:
+		for _, d := range ds {
+			if _, err := fmt.Fprintln(w, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	case diagnosticsJSON:
+		if ds == nil {
+			ds = diagnostics{}
+		}
+		return json.NewEncoder(w).Encode(ds)
+	default:
+		return fmt.Errorf("unsupported diagnostics format %q", format)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tchssk/ago/convert"
+)
+
+func TestDiagnosticsWrite(t *testing.T) {
+	ds := diagnostics{
+		{Severity: convert.SeverityWarning, Pointer: "/paths/~1users/get", Message: "first"},
+		{Severity: convert.SeverityError, Message: "second"},
+	}
+	cases := map[string]struct {
+		diagnostics diagnostics
+		format      string
+		expected    string
+	}{
+		"text": {
+			diagnostics: ds,
+			format:      diagnosticsText,
+			expected:    "warning: /paths/~1users/get: first\nerror: second\n",
+		},
+		"empty text": {
+			diagnostics: nil,
+			format:      diagnosticsText,
+			expected:    "",
+		},
+		"json": {
+			diagnostics: ds,
+			format:      diagnosticsJSON,
+			expected:    `[{"severity":"warning","pointer":"/paths/~1users/get","message":"first"},{"severity":"error","message":"second"}]` + "\n",
+		},
+		"empty json": {
+			diagnostics: nil,
+			format:      diagnosticsJSON,
+			expected:    "[]\n",
+		},
+	}
+	for k, tc := range cases {
+		var buf bytes.Buffer
+		if err := tc.diagnostics.write(&buf, tc.format); err != nil {
+			t.Fatalf("%s: write returned %s", k, err)
+		}
+		if actual := buf.String(); actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestDiagnosticsStrict(t *testing.T) {
+	ds := diagnostics{{Severity: convert.SeverityWarning, Message: "warning"}}
+	if ds.hasErrors() {
+		t.Fatalf("hasErrors returned true for a warning")
+	}
+	ds.strict()
+	if !ds.hasErrors() {
+		t.Errorf("hasErrors returned false in strict mode")
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -24,7 +25,7 @@ var RootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(-1)
 	}
 }
@@ -36,7 +37,7 @@ func init() {
 	// Cobra supports Persistent Flags, which, if defined here,
 	// will be global for your application.
 
-	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ago.yaml)")
+	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .ago.yaml in the current directory or the closest parent, then $HOME/.ago.yaml)")
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	RootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
@@ -46,14 +47,28 @@ func init() {
 func initConfig() {
 	if cfgFile != "" { // enable ability to specify config file via flag
 		viper.SetConfigFile(cfgFile)
+	} else if file, ok := findConfigFile(); ok {
+		viper.SetConfigFile(file)
+	} else {
+		// SetConfigName resets the config file, so it is only used when no
+		// file is found in the current directory or its parents.
+		viper.SetConfigName(".ago")  // name of config file (without extension)
+		viper.AddConfigPath("$HOME") // adding home directory as first search path
 	}
 
-	viper.SetConfigName(".ago")  // name of config file (without extension)
-	viper.AddConfigPath("$HOME") // adding home directory as first search path
-	viper.AutomaticEnv()         // read in environment variables that match
+	viper.SetEnvPrefix("ago") // read in environment variables prefixed with AGO_
+	viper.SetEnvKeyReplacer(configEnvReplacer)
+	for _, key := range configKeys {
+		viper.BindEnv(key)
+	}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	// If a config file is found, read it in. Messages go to stderr so that
+	// they are not mixed with the generated design.
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			log.Fatal(err)
+		}
+	} else if diagnosticsFormat != diagnosticsJSON {
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
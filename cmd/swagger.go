@@ -2,21 +2,31 @@ package cmd
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"go/format"
 	"io/ioutil"
 	"log"
 	"os"
+	"time"
 
-	"github.com/goadesign/goa/design"
-	"github.com/goadesign/goa/goagen/gen_swagger"
 	"github.com/spf13/cobra"
+	"github.com/tchssk/ago/convert"
+)
+
+var (
+	cacheDir          string
+	diagnosticsFormat string
+	fetchTimeout      time.Duration
+	inputFormat       string
+	outDir            string
+	outFile           string
+	requestHeaders    []string
+	strict            bool
+	target            string
+	templateDir       string
 )
 
 // swaggerCmd represents the swagger command
 var swaggerCmd = &cobra.Command{
-	Use:   "swagger",
+	Use:   "swagger <file|url|->",
 	Short: "Generate design from swagger definitions",
 	Long:  `Generate design from swagger definitions`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -24,29 +34,89 @@ var swaggerCmd = &cobra.Command{
 			log.Fatal("invalid file path")
 			return
 		}
-		data, err := ioutil.ReadFile(args[0])
+		if outFile != "" && outDir != "" {
+			log.Fatal("--out and --out-dir cannot be used together")
+		}
+		if diagnosticsFormat != diagnosticsText && diagnosticsFormat != diagnosticsJSON {
+			log.Fatalf("unsupported diagnostics format %q", diagnosticsFormat)
+		}
+		cfg, err := loadConfig()
 		if err != nil {
 			log.Fatal(err)
 		}
-		var swagger genswagger.Swagger
-		if err := json.Unmarshal(data, &swagger); err != nil {
-			log.Fatal(err)
+		if cmd.Flags().Changed("target") {
+			cfg.Target = target
 		}
-		buf := new(bytes.Buffer)
-		if err := tmpl.ExecuteTemplate(buf, "all", swaggerToAPI(swagger)); err != nil {
-			log.Fatal(err)
+		if cmd.Flags().Changed("templates") {
+			cfg.Templates = templateDir
+		}
+		switch {
+		case outFile != "":
+			cfg.Output.Layout, cfg.Output.Path = convert.LayoutFile, outFile
+		case outDir != "":
+			cfg.Output.Layout, cfg.Output.Path = convert.LayoutPackage, outDir
 		}
-		formated, err := format.Source(buf.Bytes())
+		ds, err := generate(args[0], cfg)
 		if err != nil {
-			fmt.Println(string(buf.Bytes()))
-			log.Fatal(err)
+			ds = append(ds, errorDiagnostic(err))
 		}
-		if _, err := bytes.NewBuffer(formated).WriteTo(os.Stdout); err != nil {
+		if err := ds.write(os.Stderr, diagnosticsFormat); err != nil {
 			log.Fatal(err)
 		}
+		if ds.hasErrors() {
+			os.Exit(1)
+		}
 	},
 }
 
+// generate writes the design of the swagger definition of source with the
+// settings of cfg. It returns the diagnostics found in the definition. In
+// strict mode, warnings are errors and nothing is written if there are any.
+func generate(source string, cfg *config) (diagnostics, error) {
+	var ds diagnostics
+	opts := cfg.options()
+	opts.Format = inputFormat
+	opts.Source = source
+	opts.Timeout = fetchTimeout
+	opts.Headers = requestHeaders
+	opts.CacheDir = cacheDir
+	opts.Warn = func(d convert.Diagnostic) { ds = append(ds, d) }
+	opts.Origins = make(map[interface{}]string)
+	data, err := convert.Load(source, opts)
+	if err != nil {
+		return nil, err
+	}
+	api, err := convert.Convert(bytes.NewReader(data), opts)
+	if err != nil {
+		return ds, err
+	}
+	if strict {
+		ds.strict()
+		if ds.hasErrors() {
+			return ds, nil
+		}
+	}
+	renderOpts := convert.RenderOptions{
+		Target:      cfg.Target,
+		Layout:      cfg.Output.Layout,
+		TemplateDir: cfg.Templates,
+		Origins:     opts.Origins,
+	}
+	if cfg.Output.Layout == convert.LayoutPackage {
+		renderOpts.Dir = cfg.Output.Path
+		return ds, convert.Render(api, nil, renderOpts)
+	}
+	buf := new(bytes.Buffer)
+	if err := convert.Render(api, buf, renderOpts); err != nil {
+		return ds, err
+	}
+	if cfg.Output.Path != "" {
+		return ds, ioutil.WriteFile(cfg.Output.Path, buf.Bytes(), 0644)
+	}
+	_, err = buf.WriteTo(os.Stdout)
+	return ds, err
+}
+
 func init() {
 	RootCmd.AddCommand(swaggerCmd)
 
@@ -60,55 +130,14 @@ func init() {
 	// is called directly, e.g.:
 	// swaggerCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
-}
-
-func swaggerToAPI(swagger genswagger.Swagger) *design.APIDefinition {
-	api := design.APIDefinition{
-		Host:     swagger.Host,
-		Schemes:  swagger.Schemes,
-		BasePath: swagger.BasePath,
-		//		Params *AttributeDefinition
-		Consumes: []*design.EncodingDefinition{
-			&design.EncodingDefinition{
-				MIMETypes: swagger.Consumes,
-				Encoder:   false,
-			},
-		},
-		Produces: []*design.EncodingDefinition{
-			&design.EncodingDefinition{
-				MIMETypes: swagger.Produces,
-				Encoder:   true,
-			},
-		},
-		//		Origins map[string]*CORSDefinition
-		//		Resources map[string]*ResourceDefinition
-		//		Types map[string]*UserTypeDefinition
-		//		MediaTypes map[string]*MediaTypeDefinition
-		//		Traits map[string]*dslengine.TraitDefinition
-		//		Responses map[string]*ResponseDefinition
-		//		ResponseTemplates map[string]*ResponseTemplateDefinition
-		//		DefaultResponses map[string]*ResponseDefinition
-		//		DefaultResponseTemplates map[string]*ResponseTemplateDefinition
-		//		DSLFunc func()
-		//		Metadata dslengine.MetadataDefinition
-		//		SecuritySchemes []*SecuritySchemeDefinition
-		//		Security *SecurityDefinition
-		//		NoExamples bool
-	}
-	if swagger.Info != nil {
-		api.Name = swagger.Info.Title
-		api.Title = swagger.Info.Title
-		api.Description = swagger.Info.Description
-		api.Version = swagger.Info.Version
-		api.TermsOfService = swagger.Info.TermsOfService
-		api.Contact = swagger.Info.Contact
-		api.License = swagger.Info.License
-	}
-	if swagger.ExternalDocs != nil {
-		api.Docs = &design.DocsDefinition{
-			Description: swagger.ExternalDocs.Description,
-			URL:         swagger.ExternalDocs.URL,
-		}
-	}
-	return &api
+	swaggerCmd.Flags().StringVar(&inputFormat, "format", "", "format of the swagger definition (json or yaml; default is detected from the file)")
+	swaggerCmd.Flags().DurationVar(&fetchTimeout, "timeout", 30*time.Second, "timeout of fetching the swagger definition from a URL")
+	swaggerCmd.Flags().StringArrayVar(&requestHeaders, "header", nil, "header to send when fetching from a URL, as \"Name: value\" (can be repeated)")
+	swaggerCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "directory to cache fetched definitions in, used when fetching fails")
+	swaggerCmd.Flags().StringVar(&outFile, "out", "", "file to write the design to (default is stdout)")
+	swaggerCmd.Flags().StringVar(&outDir, "out-dir", "", "directory to write the design to as a package of multiple files")
+	swaggerCmd.Flags().StringVar(&target, "target", convert.TargetV1, "version of goa DSL to generate (v1 or v3)")
+	swaggerCmd.Flags().StringVar(&templateDir, "templates", "", "directory of .tmpl files that replace the built-in templates of the same name")
+	swaggerCmd.Flags().BoolVar(&strict, "strict", false, "fail on warnings about unsupported constructs")
+	swaggerCmd.Flags().StringVar(&diagnosticsFormat, "diagnostics-format", diagnosticsText, "format of the diagnostics written to stderr (text or json)")
 }
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/tchssk/ago/convert"
+)
+
+var (
+	dumpForce  bool
+	dumpTarget string
+)
+
+// templatesCmd represents the templates command
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage the templates of the DSL",
+	Long:  `Manage the templates that generate the DSL. Templates are overridden with the --templates flag of the swagger command.`,
+}
+
+// templatesDumpCmd represents the templates dump command
+var templatesDumpCmd = &cobra.Command{
+	Use:   "dump <dir>",
+	Short: "Write the built-in templates to a directory",
+	Long:  `Write the built-in templates to a directory as <name>.tmpl files, which can be edited and passed to the --templates flag of the swagger command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatal("invalid directory")
+		}
+		if err := dumpTemplates(args[0], dumpTarget, dumpForce); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// dumpTemplates writes the templates of target to dir. Existing files are
+// overwritten only if force is true.
+func dumpTemplates(dir, target string, force bool) error {
+	sources, err := convert.Templates(target)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range names {
+		path := filepath.Join(dir, name+".tmpl")
+		if !force {
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+			}
+		}
+		if err := ioutil.WriteFile(path, []byte(sources[name]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesDumpCmd)
+
+	templatesDumpCmd.Flags().StringVar(&dumpTarget, "target", convert.TargetV1, "version of goa DSL of the templates (v1 or v3)")
+	templatesDumpCmd.Flags().BoolVar(&dumpForce, "force", false, "overwrite existing files")
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tchssk/ago/convert"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify <file|url|->",
+	Short: "Check that the design generates the same swagger",
+	Long:  `Convert a swagger definition, generate swagger from the design with goa and print the differences: missing paths and operations, parameters that are missing or whose type is changed, and dropped responses. Exits with 1 if there are differences.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatal("invalid file path")
+		}
+		if diagnosticsFormat != diagnosticsText && diagnosticsFormat != diagnosticsJSON {
+			log.Fatalf("unsupported diagnostics format %q", diagnosticsFormat)
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		differences, ds, err := verify(args[0], cfg)
+		if err != nil {
+			ds = append(ds, errorDiagnostic(err))
+		}
+		if err := ds.write(os.Stderr, diagnosticsFormat); err != nil {
+			log.Fatal(err)
+		}
+		if ds.hasErrors() {
+			os.Exit(1)
+		}
+		if err := writeDifferences(os.Stdout, differences, diagnosticsFormat); err != nil {
+			log.Fatal(err)
+		}
+		if len(differences) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// verify returns the differences between the swagger definition of source
+// and the swagger generated from its design, and the diagnostics found in the
+// definition.
+func verify(source string, cfg *config) ([]convert.Difference, diagnostics, error) {
+	var ds diagnostics
+	opts := cfg.options()
+	opts.Format = inputFormat
+	opts.Source = source
+	opts.Timeout = fetchTimeout
+	opts.Headers = requestHeaders
+	opts.CacheDir = cacheDir
+	opts.Warn = func(d convert.Diagnostic) { ds = append(ds, d) }
+	data, err := convert.Load(source, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	differences, err := convert.Verify(bytes.NewReader(data), opts)
+	return differences, ds, err
+}
+
+// writeDifferences writes differences to w in format. JSON is always written
+// so that it can be parsed, text only if there are differences.
+func writeDifferences(w io.Writer, differences []convert.Difference, format string) error {
+	switch format {
+	case diagnosticsText:
+		for _, d := range differences {
+			if _, err := fmt.Fprintln(w, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	case diagnosticsJSON:
+		if differences == nil {
+			differences = []convert.Difference{}
+		}
+		return json.NewEncoder(w).Encode(differences)
+	default:
+		return fmt.Errorf("unsupported diagnostics format %q", format)
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVar(&inputFormat, "format", "", "format of the swagger definition (json or yaml; default is detected from the file)")
+	verifyCmd.Flags().DurationVar(&fetchTimeout, "timeout", 30*time.Second, "timeout of fetching the swagger definition from a URL")
+	verifyCmd.Flags().StringArrayVar(&requestHeaders, "header", nil, "header to send when fetching from a URL, as \"Name: value\" (can be repeated)")
+	verifyCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "directory to cache fetched definitions in, used when fetching fails")
+	verifyCmd.Flags().StringVar(&diagnosticsFormat, "diagnostics-format", diagnosticsText, "format of the diagnostics and the differences (text or json)")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tchssk/ago/convert"
+)
+
+func TestWriteDifferences(t *testing.T) {
+	differences := []convert.Difference{
+		{Pointer: "/paths/~1users", Message: `path "/users" is missing`},
+		{Pointer: "/paths/~1groups/get/responses/404", Message: `response "404" is dropped`},
+	}
+	cases := map[string]struct {
+		differences []convert.Difference
+		format      string
+		expected    string
+	}{
+		"text": {
+			differences: differences,
+			format:      diagnosticsText,
+			expected:    "/paths/~1users: path \"/users\" is missing\n/paths/~1groups/get/responses/404: response \"404\" is dropped\n",
+		},
+		"empty text": {
+			differences: nil,
+			format:      diagnosticsText,
+			expected:    "",
+		},
+		"json": {
+			differences: differences,
+			format:      diagnosticsJSON,
+			expected:    `[{"pointer":"/paths/~1users","message":"path \"/users\" is missing"},{"pointer":"/paths/~1groups/get/responses/404","message":"response \"404\" is dropped"}]` + "\n",
+		},
+		"empty json": {
+			differences: nil,
+			format:      diagnosticsJSON,
+			expected:    "[]\n",
+		},
+	}
+	for k, tc := range cases {
+		var buf bytes.Buffer
+		if err := writeDifferences(&buf, tc.differences, tc.format); err != nil {
+			t.Fatalf("%s: writeDifferences returned %s", k, err)
+		}
+		if actual := buf.String(); actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
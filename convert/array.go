@@ -0,0 +1,160 @@
+package convert
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+// itemsToArray converts the items of the array schema being converted. Items
+// that are inline objects become user types because ArrayOf only accepts
+// types.
+func (c *converter) itemsToArray(schema *genschema.JSONSchema) (*design.Array, error) {
+	if schema.Items == nil {
+		c.warnf("array has no items, its elements are converted to Any")
+		return &design.Array{
+			ElemType: &design.AttributeDefinition{
+				Type: design.Any,
+			},
+		}, nil
+	}
+	defer c.at("items")()
+	elem, err := c.schemaToAttribute(schema.Items)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := elem.Type.(design.Object); ok {
+		elem = &design.AttributeDefinition{
+			Type: c.itemType(elem),
+		}
+		c.record(elem)
+	}
+	return &design.Array{
+		ElemType: elem,
+	}, nil
+}
+
+// itemType returns the user type of the inline object items being
+// converted. Definitions that are inlined several times share the type of
+// their items.
+func (c *converter) itemType(attribute *design.AttributeDefinition) *design.UserTypeDefinition {
+	pointer := jsonPointer(c.pointer)
+	if t, ok := c.itemTypes[pointer]; ok {
+		return t
+	}
+	t := c.newType(attribute, c.itemTypeName())
+	c.itemTypes[pointer] = t
+	return t
+}
+
+// itemTypeName returns the name of the type of the inline object items being
+// converted. It is made of the names of the definition or the action and of
+// the properties that contain the items, followed by Item.
+func (c *converter) itemTypeName() string {
+	var parts []string
+	tokens := c.pointer
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "definitions", "properties":
+			if i+1 < len(tokens) {
+				i++
+				parts = append(parts, codegen.Goify(tokens[i], true))
+			}
+		case "paths":
+			i++
+			parts = append(parts, codegen.Goify(c.action, true))
+		case "parameters":
+			i++
+			parts = append(parts, c.naming.PayloadSuffix)
+		case "responses":
+			if i+1 < len(tokens) {
+				i++
+				status, _ := strconv.Atoi(tokens[i])
+				if name, ok := responseNames[status]; ok {
+					parts = append(parts, name)
+				}
+			}
+		case "additionalProperties":
+			parts = append(parts, "Value")
+		case "allOf":
+			i++
+		}
+	}
+	return strings.Join(parts, "") + "Item"
+}
+
+// collectionOf returns the collection media type of elem, which CollectionOf
+// describes. Collections are not listed in the media types of the API because
+// goa defines them from their elements. The default view renders the elements
+// with their default view, like the one that CollectionOf defines.
+func (c *converter) collectionOf(elem *design.MediaTypeDefinition) *design.MediaTypeDefinition {
+	collection := &design.MediaTypeDefinition{
+		UserTypeDefinition: &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: &design.Array{
+					ElemType: &design.AttributeDefinition{
+						Type: elem,
+					},
+				},
+			},
+			TypeName: elem.TypeName + "Collection",
+		},
+		Identifier:  elem.Identifier + "; type=collection",
+		ContentType: elem.ContentType,
+	}
+	collection.Views = map[string]*design.ViewDefinition{
+		"default": &design.ViewDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: &design.Array{
+					ElemType: &design.AttributeDefinition{
+						Type: elem,
+						View: "default",
+					},
+				},
+			},
+			Name:   "default",
+			Parent: collection,
+		},
+	}
+	c.record(collection)
+	return collection
+}
+
+// collectionElem returns the media type of the elements of mediaType if it is
+// a collection.
+func collectionElem(mediaType *design.MediaTypeDefinition) (*design.MediaTypeDefinition, bool) {
+	if mediaType.UserTypeDefinition == nil || mediaType.AttributeDefinition == nil {
+		return nil, false
+	}
+	array, ok := mediaType.Type.(*design.Array)
+	if !ok {
+		return nil, false
+	}
+	elem, ok := array.ElemType.Type.(*design.MediaTypeDefinition)
+	return elem, ok
+}
+
+// itemsToSchema returns the schema that describes the items of a non-body
+// parameter or of a header.
+func itemsToSchema(items *genswagger.Items) *genschema.JSONSchema {
+	if items == nil {
+		return nil
+	}
+	return &genschema.JSONSchema{
+		Type:      genschema.JSONType(items.Type),
+		Items:     itemsToSchema(items.Items),
+		Enum:      items.Enum,
+		Format:    items.Format,
+		Pattern:   items.Pattern,
+		Minimum:   items.Minimum,
+		Maximum:   items.Maximum,
+		MinLength: items.MinLength,
+		MaxLength: items.MaxLength,
+		MinItems:  items.MinItems,
+		MaxItems:  items.MaxItems,
+	}
+}
@@ -0,0 +1,122 @@
+package convert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+func TestItemTypeName(t *testing.T) {
+	cases := map[string]struct {
+		pointer  []string
+		expected string
+	}{
+		"definition":     {[]string{"definitions", "Pet", "properties", "toys", "items"}, "PetToysItem"},
+		"nested arrays":  {[]string{"definitions", "Pet", "items", "items"}, "PetItem"},
+		"hash values":    {[]string{"definitions", "Pet", "additionalProperties", "items"}, "PetValueItem"},
+		"allOf":          {[]string{"definitions", "Pet", "allOf", "1", "properties", "toys", "items"}, "PetToysItem"},
+		"payload":        {[]string{"paths", "/pets", "post", "parameters", "0", "schema", "items"}, "CreatePayloadItem"},
+		"response":       {[]string{"paths", "/pets", "post", "responses", "201", "schema", "items"}, "CreateCreatedItem"},
+		"custom status":  {[]string{"paths", "/pets", "post", "responses", "299", "schema", "items"}, "CreateItem"},
+		"top parameters": {[]string{"parameters", "pets", "schema", "items"}, "PayloadItem"},
+	}
+	for k, tc := range cases {
+		c := newConverter(genswagger.Swagger{})
+		c.action = "create"
+		c.pointer = tc.pointer
+		if actual := c.itemTypeName(); actual != tc.expected {
+			t.Errorf("%s: got %s, expected %s", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestItemsToSchema(t *testing.T) {
+	max := 3
+	items := &genswagger.Items{
+		Type:     "array",
+		MaxItems: &max,
+		Items: &genswagger.Items{
+			Type: "string",
+			Enum: []interface{}{"a", "b"},
+		},
+	}
+	expected := &genschema.JSONSchema{
+		Type:     genschema.Array,
+		MaxItems: &max,
+		Items: &genschema.JSONSchema{
+			Type: genschema.String,
+			Enum: []interface{}{"a", "b"},
+		},
+	}
+	if actual := itemsToSchema(items); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("\ngot:\n%#v\nexpected:\n%#v", actual, expected)
+	}
+	if actual := itemsToSchema(nil); actual != nil {
+		t.Errorf("got %#v, expected nil", actual)
+	}
+}
+
+func TestConvertArrays(t *testing.T) {
+	document := `swagger: "2.0"
+paths:
+  /pets:
+    get:
+      operationId: list
+      parameters:
+      - {name: tags, in: query, type: array, items: {type: string, enum: [cat, dog]}, maxItems: 5}
+      responses:
+        "200": {description: OK, schema: {type: array, items: {$ref: "#/definitions/Pet"}}}
+        "206": {description: Partial, schema: {$ref: "#/definitions/Pets"}}
+definitions:
+  Pets: {type: array, items: {$ref: "#/definitions/Pet"}}
+  Pet:
+    type: object
+    properties:
+      matrix: {type: array, items: {type: array, items: {type: integer}}, minItems: 1}
+      toys: {type: array, items: {type: object, properties: {kind: {type: string}}}}
+`
+	api, err := Convert(strings.NewReader(document), Options{})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	list := api.Resources["pets"].Actions["list"]
+	tags := list.Params.Type.ToObject()["tags"]
+	if array, ok := tags.Type.(*design.Array); !ok || array.ElemType.Type != design.String || *tags.Validation.MaxLength != 5 {
+		t.Errorf("unexpected tags %#v", tags)
+	}
+	if elem := tags.Type.ToArray().ElemType; elem.Validation == nil || !reflect.DeepEqual(elem.Validation.Values, []interface{}{"cat", "dog"}) {
+		t.Errorf("unexpected elements of tags %#v", elem)
+	}
+	pet := api.Types["Pet"].Type.ToObject()
+	matrix, ok := pet["matrix"].Type.(*design.Array)
+	if !ok || *pet["matrix"].Validation.MinLength != 1 {
+		t.Fatalf("unexpected matrix %#v", pet["matrix"])
+	}
+	if inner, ok := matrix.ElemType.Type.(*design.Array); !ok || inner.ElemType.Type != design.Integer {
+		t.Errorf("unexpected elements of matrix %#v", matrix.ElemType.Type)
+	}
+	toys, ok := pet["toys"].Type.(*design.Array)
+	if !ok || toys.ElemType.Type != api.Types["PetToysItem"] {
+		t.Errorf("unexpected toys %#v, types %v", pet["toys"].Type, api.Types)
+	}
+	petMedia := api.MediaTypes["application/vnd.pet"]
+	for _, name := range []string{"OK", "PartialContent"} {
+		collection, ok := list.Responses[name].Type.(*design.MediaTypeDefinition)
+		if !ok {
+			t.Fatalf("%s: unexpected type %#v", name, list.Responses[name].Type)
+		}
+		if elem, ok := collectionElem(collection); !ok || elem != petMedia || collection.Identifier != "application/vnd.pet+json; type=collection" {
+			t.Errorf("%s: got %s, expected the collection of PetMedia", name, collection.Identifier)
+		}
+		if view := collection.Views["default"]; view == nil || view.Type.ToArray().ElemType.View != "default" {
+			t.Errorf("%s: unexpected views %v", name, collection.Views)
+		}
+	}
+	if len(api.MediaTypes) != 1 {
+		t.Errorf("unexpected media types %v", api.MediaTypes)
+	}
+}
@@ -0,0 +1,242 @@
+package convert
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// bundler merges the documents that a swagger document refers to with
+// external references (e.g. ./models/user.yaml#/User) into the document.
+// Object schemas become definitions of the document so that they are
+// converted to user types, the other values are inlined.
+type bundler struct {
+	loader *documentLoader
+	// definitions holds the definitions of the document and definitionsRef
+	// is the prefix of the references to them.
+	definitions    map[string]interface{}
+	definitionsRef string
+	// documents holds the external documents by location.
+	documents map[string]interface{}
+	// names holds the names of the definitions added for external schemas by
+	// the location of the schemas.
+	names map[string]string
+	// importing holds the locations being inlined to detect cycles.
+	importing []string
+}
+
+// bundle merges the external documents that document refers to into
+// document. source is the location of document, which relative references
+// are resolved against.
+func (l *documentLoader) bundle(document interface{}, source string) (interface{}, error) {
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return document, nil
+	}
+	b := &bundler{
+		loader:    l,
+		documents: make(map[string]interface{}),
+		names:     make(map[string]string),
+	}
+	if isOpenAPI3(document) {
+		components, ok := root["components"].(map[string]interface{})
+		if !ok {
+			components = make(map[string]interface{})
+		}
+		b.definitions, ok = components["schemas"].(map[string]interface{})
+		if !ok {
+			b.definitions = make(map[string]interface{})
+		}
+		b.definitionsRef = "#/components/schemas/"
+		defer func() {
+			if len(b.definitions) > 0 {
+				components["schemas"] = b.definitions
+				root["components"] = components
+			}
+		}()
+	} else {
+		b.definitions, ok = root["definitions"].(map[string]interface{})
+		if !ok {
+			b.definitions = make(map[string]interface{})
+		}
+		b.definitionsRef = definitionsPrefix
+		defer func() {
+			if len(b.definitions) > 0 {
+				root["definitions"] = b.definitions
+			}
+		}()
+	}
+	return b.process(root, source, false)
+}
+
+// process replaces the external references in node, which belongs to the
+// document at base. Local references are external too when they appear in
+// an external document.
+func (b *bundler) process(node interface{}, base string, external bool) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok {
+			if !external && strings.HasPrefix(ref, "#") {
+				return n, nil
+			}
+			return b.follow(ref, base)
+		}
+		for k, v := range n {
+			processed, err := b.process(v, base, external)
+			if err != nil {
+				return nil, err
+			}
+			n[k] = processed
+		}
+	case []interface{}:
+		for i, v := range n {
+			processed, err := b.process(v, base, external)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = processed
+		}
+	}
+	return node, nil
+}
+
+// follow returns the value that replaces the external reference ref found in
+// the document at base.
+func (b *bundler) follow(ref, base string) (interface{}, error) {
+	file, fragment := resolveLocation(base, ref)
+	location := file + "#" + fragment
+	document, err := b.document(file)
+	if err != nil {
+		return nil, err
+	}
+	target, err := lookupPointer(document, "#"+fragment)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	if isObjectSchemaValue(target) {
+		name, ok := b.names[location]
+		if !ok {
+			name = b.definitionName(file, fragment)
+			b.names[location] = name
+			// Reserve the name before processing the schema so that it can
+			// refer to itself.
+			b.definitions[name] = nil
+			definition, err := b.process(copyValue(target), file, true)
+			if err != nil {
+				return nil, err
+			}
+			b.definitions[name] = definition
+		}
+		escaped := strings.Replace(strings.Replace(name, "~", "~0", -1), "/", "~1", -1)
+		return map[string]interface{}{"$ref": b.definitionsRef + escaped}, nil
+	}
+	for i, importing := range b.importing {
+		if importing == location {
+			return nil, fmt.Errorf("circular import: %s", strings.Join(append(b.importing[i:], location), " -> "))
+		}
+	}
+	b.importing = append(b.importing, location)
+	defer func() { b.importing = b.importing[:len(b.importing)-1] }()
+	return b.process(copyValue(target), file, true)
+}
+
+// document returns the external document at location, which is loaded once.
+func (b *bundler) document(location string) (interface{}, error) {
+	if document, ok := b.documents[location]; ok {
+		return document, nil
+	}
+	data, err := b.loader.load(location)
+	if err != nil {
+		return nil, err
+	}
+	document, err := decodeDocument(data, detectFormat(sourcePath(location), data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", location, err)
+	}
+	b.documents[location] = document
+	return document, nil
+}
+
+// definitionName returns an unused name for the definition of the schema at
+// fragment of file. It is the last token of fragment, or the base name of
+// file if the schema is the whole document.
+func (b *bundler) definitionName(file, fragment string) string {
+	base := fragment[strings.LastIndex(fragment, "/")+1:]
+	base = strings.Replace(strings.Replace(base, "~1", "/", -1), "~0", "~", -1)
+	if base == "" {
+		base = strings.TrimSuffix(filepath.Base(sourcePath(file)), filepath.Ext(sourcePath(file)))
+	}
+	name := base
+	for i := 2; ; i++ {
+		if _, ok := b.definitions[name]; !ok {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+}
+
+// resolveLocation returns the document and the fragment that ref points to
+// relative to the document at base.
+func resolveLocation(base, ref string) (string, string) {
+	file, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		file, fragment = ref[:i], ref[i+1:]
+	}
+	switch {
+	case file == "":
+		return base, fragment
+	case isURL(file):
+		return file, fragment
+	case isURL(base):
+		if b, err := url.Parse(base); err == nil {
+			if u, err := b.Parse(file); err == nil {
+				return u.String(), fragment
+			}
+		}
+		return file, fragment
+	case filepath.IsAbs(file):
+		return filepath.Clean(file), fragment
+	}
+	dir := "."
+	if base != stdinSource {
+		dir = filepath.Dir(base)
+	}
+	return filepath.Join(dir, filepath.FromSlash(file)), fragment
+}
+
+// isObjectSchemaValue returns true if v is a generic value of an object
+// schema with properties.
+func isObjectSchemaValue(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if _, ok := m["$ref"]; ok {
+		return false
+	}
+	if t, ok := m["type"]; ok && t != "object" {
+		return false
+	}
+	properties, ok := m["properties"].(map[string]interface{})
+	return ok && len(properties) > 0
+}
+
+// copyValue returns a deep copy of a generic value.
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[k] = copyValue(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, v := range t {
+			s[i] = copyValue(v)
+		}
+		return s
+	}
+	return v
+}
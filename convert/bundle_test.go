@@ -0,0 +1,104 @@
+package convert
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBundle(t *testing.T) {
+	cases := map[string]struct {
+		files    map[string]string
+		expected string
+		err      string
+	}{
+		"with schemas": {
+			files: map[string]string{
+				"api.json":         `{"swagger":"2.0","paths":{"/users":{"get":{"responses":{"200":{"schema":{"$ref":"models/user.yaml#/User"}}}}}},"definitions":{"Group":{"type":"string"}}}`,
+				"models/user.yaml": "User:\n  type: object\n  properties:\n    group: {$ref: '#/Group'}\n    tags: {$ref: 'tags.json'}\nGroup:\n  type: object\n  properties:\n    id: {type: integer}\n",
+				"models/tags.json": `{"type":"array","items":{"type":"string"}}`,
+			},
+			expected: `{"swagger":"2.0","paths":{"/users":{"get":{"responses":{"200":{"schema":{"$ref":"#/definitions/User"}}}}}},"definitions":{"Group":{"type":"string"},"User":{"type":"object","properties":{"group":{"$ref":"#/definitions/Group2"},"tags":{"type":"array","items":{"type":"string"}}}},"Group2":{"type":"object","properties":{"id":{"type":"integer"}}}}}`,
+		},
+		"with parameters": {
+			files: map[string]string{
+				"api.json":    `{"swagger":"2.0","paths":{"/users":{"get":{"parameters":[{"$ref":"common.json#/parameters/limit"},{"$ref":"#/parameters/offset"}]}}}}`,
+				"common.json": `{"parameters":{"limit":{"name":"limit","in":"query","type":"integer"}}}`,
+			},
+			expected: `{"swagger":"2.0","paths":{"/users":{"get":{"parameters":[{"name":"limit","in":"query","type":"integer"},{"$ref":"#/parameters/offset"}]}}}}`,
+		},
+		"with circular imports": {
+			files: map[string]string{
+				"api.json": `{"swagger":"2.0","parameters":{"a":{"$ref":"a.json#/a"}}}`,
+				"a.json":   `{"a":{"schema":{"$ref":"b.json#/b"}}}`,
+				"b.json":   `{"b":{"items":{"$ref":"a.json#/a"}}}`,
+			},
+			err: "circular import",
+		},
+	}
+	loader, err := newDocumentLoader(time.Second, nil, "")
+	if err != nil {
+		t.Fatalf("newDocumentLoader returned %s", err)
+	}
+	for k, tc := range cases {
+		dir, err := ioutil.TempDir("", "bundle")
+		if err != nil {
+			t.Fatalf("%s: TempDir returned %s", k, err)
+		}
+		defer os.RemoveAll(dir)
+		for name, content := range tc.files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("%s: MkdirAll returned %s", k, err)
+			}
+			if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("%s: WriteFile returned %s", k, err)
+			}
+		}
+		var document interface{}
+		if err := json.Unmarshal([]byte(tc.files["api.json"]), &document); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		actual, err := loader.bundle(document, filepath.Join(dir, "api.json"))
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: got error %v, expected %q", k, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: bundle returned %s", k, err)
+		}
+		var expected interface{}
+		if err := json.Unmarshal([]byte(tc.expected), &expected); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			data, _ := json.Marshal(actual)
+			t.Errorf("%s: \ngot:\n%s\nexpected:\n%s", k, data, tc.expected)
+		}
+	}
+}
+
+func TestResolveLocation(t *testing.T) {
+	cases := map[string]struct {
+		base, ref      string
+		file, fragment string
+	}{
+		"local":    {"api/swagger.yaml", "#/definitions/Pet", "api/swagger.yaml", "/definitions/Pet"},
+		"relative": {"api/swagger.yaml", "../models/pet.yaml#/Pet", "models/pet.yaml", "/Pet"},
+		"stdin":    {"-", "models/pet.yaml", "models/pet.yaml", ""},
+		"url":      {"https://example.com/api/swagger.yaml", "models/pet.yaml#/Pet", "https://example.com/api/models/pet.yaml", "/Pet"},
+	}
+	for k, tc := range cases {
+		file, fragment := resolveLocation(tc.base, tc.ref)
+		if file != tc.file || fragment != tc.fragment {
+			t.Errorf("%s: got %s#%s, expected %s#%s", k, file, fragment, tc.file, tc.fragment)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package convert
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+// allOf returns the schemas that the schema being converted is composed of,
+// or nil if it is not composed. genschema.JSONSchema does not hold allOf, so
+// it is read from the node.
+func (c *converter) allOf() []interface{} {
+	object, _ := c.node().(map[string]interface{})
+	schemas, _ := object["allOf"].([]interface{})
+	return schemas
+}
+
+// allOfToAttribute converts schema, which is composed of the schemas of
+// allOf. The properties of the schemas and of schema are merged into an
+// object, and the first definition referred to becomes the reference of the
+// object, which types render with Reference. Objects that are not types are
+// flattened. A property that is defined with different types is warned about
+// and the first definition is kept.
+func (c *converter) allOfToAttribute(schema *genschema.JSONSchema, allOf []interface{}) (*design.AttributeDefinition, error) {
+	if ref, ok := singleReference(schema, allOf); ok {
+		return c.referenceToAttribute(&genschema.JSONSchema{
+			Ref:         ref,
+			Description: schema.Description,
+		})
+	}
+	attribute := &design.AttributeDefinition{
+		Description: schema.Description,
+		Metadata:    c.metadataExtension(),
+	}
+	c.record(attribute)
+	m := &merger{
+		converter: c,
+		object:    make(design.Object),
+		origins:   make(map[string]string),
+	}
+	for i, v := range allOf {
+		back := c.at("allOf", strconv.Itoa(i))
+		element, err := decodeSchema(v)
+		if err != nil {
+			return nil, c.errorf("invalid schema: %s", err)
+		}
+		var a *design.AttributeDefinition
+		if element.Ref != "" {
+			if name, ok := definitionName(element.Ref); ok && attribute.Reference == nil {
+				if t, ok := c.types[name]; ok {
+					attribute.Reference = t
+				}
+			}
+			a, err = c.inlineDefinition(element.Ref)
+		} else {
+			a, err = c.schemaToAttribute(element)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.merge(a)
+		if attribute.Description == "" && element.Ref == "" {
+			attribute.Description = a.Description
+		}
+		back()
+	}
+	if len(schema.Properties) > 0 {
+		object, err := c.propertiesToObject(schema.Properties)
+		if err != nil {
+			return nil, err
+		}
+		m.merge(&design.AttributeDefinition{Type: object})
+	}
+	attribute.Type = m.object
+	own := *schema
+	own.Required = m.required(schema.Required)
+	attribute.Validation = schemaToValidation(&own)
+	c.applyValues(attribute)
+	return attribute, nil
+}
+
+// singleReference returns the reference of allOf if schema only adds a
+// description to a single reference, which is a common way to document it.
+func singleReference(schema *genschema.JSONSchema, allOf []interface{}) (string, bool) {
+	if len(allOf) != 1 || len(schema.Properties) > 0 || len(schema.Required) > 0 {
+		return "", false
+	}
+	element, _ := allOf[0].(map[string]interface{})
+	ref, ok := element["$ref"].(string)
+	return ref, ok
+}
+
+// merger merges the properties of the schemas of allOf.
+type merger struct {
+	converter *converter
+	object    design.Object
+	// origins maps the names of the properties to the JSON pointers of
+	// their first definitions.
+	origins map[string]string
+	// requiredNames holds the names of the required properties.
+	requiredNames []string
+}
+
+// merge adds the properties of a, converted from the node the converter is
+// at, to the merged object.
+func (m *merger) merge(a *design.AttributeDefinition) {
+	c := m.converter
+	if a.Validation != nil {
+		m.requiredNames = append(m.requiredNames, a.Validation.Required...)
+	}
+	object, ok := a.Type.(design.Object)
+	if !ok {
+		if a.Type != design.Any {
+			c.warnf("schema of type %s cannot be merged into an object and is ignored", a.Type.Name())
+		}
+		return
+	}
+	var names []string
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property := object[name]
+		back := c.at("properties", name)
+		if existing, ok := m.object[name]; ok {
+			if !sameType(existing.Type, property.Type) {
+				c.warnf("property %q of type %s conflicts with the property of type %s at %s and is ignored", name, property.Type.Name(), existing.Type.Name(), m.origins[name])
+			}
+		} else {
+			m.object[name] = property
+			m.origins[name] = jsonPointer(c.pointer)
+		}
+		back()
+	}
+}
+
+// required returns the sorted names of the required properties of the merged
+// object, including the names of own.
+func (m *merger) required(own []string) []string {
+	var required []string
+	for _, name := range append(m.requiredNames, own...) {
+		if _, ok := m.object[name]; ok && !containsString(required, name) {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	return required
+}
+
+// sameType returns true if a and b describe the same type. Objects are the
+// same if their attributes have the same names and types.
+func sameType(a, b design.DataType) bool {
+	switch a := a.(type) {
+	case design.Primitive:
+		b, ok := b.(design.Primitive)
+		return ok && a.Kind() == b.Kind()
+	case design.Object:
+		b, ok := b.(design.Object)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, attribute := range a {
+			other, ok := b[name]
+			if !ok || !sameType(attribute.Type, other.Type) {
+				return false
+			}
+		}
+		return true
+	case *design.Array:
+		b, ok := b.(*design.Array)
+		return ok && sameType(a.ElemType.Type, b.ElemType.Type)
+	case *design.Hash:
+		b, ok := b.(*design.Hash)
+		return ok && sameType(a.KeyType.Type, b.KeyType.Type) && sameType(a.ElemType.Type, b.ElemType.Type)
+	}
+	return a == b
+}
+
+// decodeSchema decodes v, a schema decoded into generic values.
+func decodeSchema(v interface{}) (*genschema.JSONSchema, error) {
+	data, err := json.Marshal(boolAdditionalProperties(v, false))
+	if err != nil {
+		return nil, err
+	}
+	var schema genschema.JSONSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
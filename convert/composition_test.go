@@ -0,0 +1,119 @@
+package convert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+)
+
+func TestConvertAllOf(t *testing.T) {
+	document := `swagger: "2.0"
+paths: {}
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string}
+      age: {type: integer}
+  Dog:
+    description: A dog
+    allOf:
+    - $ref: "#/definitions/Pet"
+    - type: object
+      required: [breed]
+      properties:
+        breed: {type: string}
+        age: {type: string}
+    properties:
+      owner:
+        description: The owner
+        allOf: [{$ref: "#/definitions/Pet"}]
+      tags:
+        allOf:
+        - {properties: {label: {type: string}}}
+        - {properties: {color: {type: string}}, required: [color]}
+`
+	var warnings []Diagnostic
+	api, err := Convert(strings.NewReader(document), Options{
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	dog := api.Types["Dog"]
+	if dog == nil {
+		t.Fatalf("unexpected types %v", api.Types)
+	}
+	if dog.Reference != api.Types["Pet"] {
+		t.Errorf("got reference %v, expected Pet", dog.Reference)
+	}
+	if dog.Description != "A dog" {
+		t.Errorf("got description %q, expected A dog", dog.Description)
+	}
+	object := dog.Type.ToObject()
+	if len(object) != 5 || object["age"].Type != design.Integer {
+		t.Errorf("unexpected attributes %v", object)
+	}
+	if !reflect.DeepEqual(dog.Validation.Required, []string{"breed", "name"}) {
+		t.Errorf("got required %v, expected breed and name", dog.Validation.Required)
+	}
+	if owner := object["owner"]; owner.Type != api.Types["Pet"] || owner.Description != "The owner" {
+		t.Errorf("unexpected owner %v", owner)
+	}
+	tags := object["tags"]
+	if tags.Reference != nil || len(tags.Type.ToObject()) != 2 || !reflect.DeepEqual(tags.Validation.Required, []string{"color"}) {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	expected := []Diagnostic{{
+		Severity: SeverityWarning,
+		Pointer:  "/definitions/Dog/allOf/1/properties/age",
+		Message:  `property "age" of type string conflicts with the property of type integer at /definitions/Dog/allOf/0/properties/age and is ignored`,
+	}}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("\ngot:\n%v\nexpected:\n%v", warnings, expected)
+	}
+
+	document = `swagger: "2.0"
+paths: {}
+definitions:
+  A: {allOf: [{$ref: "#/definitions/B"}, {properties: {a: {type: string}}}]}
+  B: {allOf: [{$ref: "#/definitions/A"}, {properties: {b: {type: string}}}]}
+`
+	if _, err := Convert(strings.NewReader(document), Options{}); err == nil {
+		t.Errorf("Convert accepted circular allOf")
+	}
+}
+
+func TestSameType(t *testing.T) {
+	pet := &design.UserTypeDefinition{TypeName: "Pet"}
+	cases := map[string]struct {
+		a, b     design.DataType
+		expected bool
+	}{
+		"same primitives":      {design.String, design.String, true},
+		"different primitives": {design.String, design.Integer, false},
+		"same user types":      {pet, pet, true},
+		"different user types": {pet, &design.UserTypeDefinition{TypeName: "Pet"}, false},
+		"same objects": {
+			design.Object{"name": {Type: design.String}},
+			design.Object{"name": {Type: design.String, Description: "Name"}},
+			true,
+		},
+		"different objects": {
+			design.Object{"name": {Type: design.String}},
+			design.Object{"name": {Type: design.Integer}},
+			false,
+		},
+		"object and primitive": {design.Object{}, design.Any, false},
+	}
+	for k, tc := range cases {
+		if actual := sameType(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("%s: got %t, expected %t", k, actual, tc.expected)
+		}
+	}
+}
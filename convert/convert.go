@@ -0,0 +1,229 @@
+// Package convert converts swagger definitions to goa designs and renders
+// the designs as goa DSL.
+package convert
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Layouts of the rendered design.
+const (
+	// LayoutFile renders the design as a single file.
+	LayoutFile = "file"
+	// LayoutPackage renders the design as a package of multiple files:
+	// api.go, one <name>_resource.go per resource, types.go and
+	// media_types.go.
+	LayoutPackage = "package"
+)
+
+// Strategies to group operations into resources.
+const (
+	// GroupByPath groups operations by the first segment of their path.
+	GroupByPath = "path"
+	// GroupByTag groups operations by their first tag.
+	GroupByTag = "tag"
+	// GroupByExtension groups operations by their x-goa-resource vendor
+	// extension.
+	GroupByExtension = "extension"
+)
+
+// Options are the options of Convert.
+type Options struct {
+	// Format is the format of the document, FormatJSON or FormatYAML. It is
+	// detected from Source and the content if empty.
+	Format string
+	// Source is the location of the document, a file path, an HTTP(S) URL
+	// or "-" for stdin. External references are resolved relative to it, or
+	// to the current directory if empty.
+	Source string
+	// Timeout limits the requests of documents to HTTP(S) URLs. Zero means
+	// no timeout.
+	Timeout time.Duration
+	// Headers are sent with the requests, formatted as "Name: value".
+	Headers []string
+	// CacheDir is the directory where fetched documents are cached. The
+	// cached copy is used when a document cannot be fetched.
+	CacheDir string
+	// Naming holds the rules to name the generated definitions.
+	Naming Naming
+	// TypeNames maps the names of definitions to the names of their types
+	// and media types.
+	TypeNames map[string]string
+	// Formats overrides the conversion of the formats of swagger and adds
+	// custom formats. Schemas of the formats that are not mapped keep their
+	// type with a warning.
+	Formats map[string]FormatMapping
+	// IgnorePaths and IgnoreTags are the patterns, with the syntax of
+	// path.Match, of the paths and of the tags of the operations that are
+	// not converted.
+	IgnorePaths []string
+	IgnoreTags  []string
+	// Grouping is the strategy to group operations into resources,
+	// GroupByPath, GroupByTag or GroupByExtension. Operations without a tag
+	// or the extension are grouped by path. It is GroupByPath if empty.
+	Grouping string
+	// DefaultSecurity is the name of the security scheme of the API when the
+	// document has no security requirements.
+	DefaultSecurity string
+	// Target is the version of goa DSL the design is rendered to, TargetV1
+	// or TargetV3. The constructs that the target cannot express are warned
+	// about.
+	Target string
+	// Warn, if not nil, is called with the warnings about the constructs
+	// that cannot be converted.
+	Warn func(Diagnostic)
+	// Origins, if not nil, is filled with the JSON pointers of the swagger
+	// nodes that the definitions are converted from. Passing it to Render
+	// lets syntax errors refer to the swagger nodes.
+	Origins map[interface{}]string
+}
+
+// Naming holds the rules to name the generated definitions.
+type Naming struct {
+	// MediaTypeSuffix is appended to the names of media types. It is
+	// "Media" if empty.
+	MediaTypeSuffix string
+	// PayloadSuffix is appended to the names of the inline payloads, which
+	// are named after their action. It is "Payload" if empty.
+	PayloadSuffix string
+}
+
+// defaultNaming holds the default naming rules.
+var defaultNaming = Naming{
+	MediaTypeSuffix: "Media",
+	PayloadSuffix:   "Payload",
+}
+
+// withDefaults returns n whose empty rules are replaced with the defaults.
+func (n Naming) withDefaults() Naming {
+	if n.MediaTypeSuffix == "" {
+		n.MediaTypeSuffix = defaultNaming.MediaTypeSuffix
+	}
+	if n.PayloadSuffix == "" {
+		n.PayloadSuffix = defaultNaming.PayloadSuffix
+	}
+	return n
+}
+
+// RenderOptions are the options of Render.
+type RenderOptions struct {
+	// Target is the version of goa DSL to render, TargetV1 or TargetV3. It
+	// is TargetV1 if empty.
+	Target string
+	// Layout is LayoutFile or LayoutPackage. It is LayoutFile if empty.
+	Layout string
+	// Dir is the directory where the files of LayoutPackage are written.
+	Dir string
+	// TemplateDir is a directory of .tmpl files that replace the templates
+	// named after them (e.g. action.tmpl replaces the template "action").
+	// The sources of the templates are returned by Templates.
+	TemplateDir string
+	// Origins are the origins filled by Convert.
+	Origins map[interface{}]string
+}
+
+// Load reads the document at source, a file path, an HTTP(S) URL or "-" for
+// stdin. Documents at URLs are fetched with the options of opts.
+func Load(source string, opts Options) ([]byte, error) {
+	loader, err := newDocumentLoader(opts.Timeout, opts.Headers, opts.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+	return loader.load(source)
+}
+
+// Convert reads a swagger 2.0 or OpenAPI 3.x document in JSON or YAML from r
+// and converts it to an API. The errors about nodes of the document are
+// *Error.
+func Convert(r io.Reader, opts Options) (*design.APIDefinition, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	_, _, api, err := convert(data, opts)
+	return api, err
+}
+
+// convert converts data like Convert. It also returns the converter and the
+// swagger that the API is converted from.
+func convert(data []byte, opts Options) (*converter, swaggerDocument, *design.APIDefinition, error) {
+	switch opts.Grouping {
+	case "", GroupByPath, GroupByTag, GroupByExtension:
+	default:
+		return nil, swaggerDocument{}, nil, fmt.Errorf("unsupported grouping %q", opts.Grouping)
+	}
+	formats, err := mergeFormats(opts.Formats)
+	if err != nil {
+		return nil, swaggerDocument{}, nil, err
+	}
+	loader, err := newDocumentLoader(opts.Timeout, opts.Headers, opts.CacheDir)
+	if err != nil {
+		return nil, swaggerDocument{}, nil, err
+	}
+	source := opts.Source
+	if source == "" {
+		source = stdinSource
+	}
+	swagger, err := loader.loadSwagger(data, source, opts.Format)
+	if err != nil {
+		return nil, swagger, nil, err
+	}
+	c := newConverter(swagger.Swagger)
+	c.naming = opts.Naming.withDefaults()
+	c.typeNames = opts.TypeNames
+	c.formats = formats
+	c.ignoredPaths = opts.IgnorePaths
+	c.ignoredTags = opts.IgnoreTags
+	c.grouping = opts.Grouping
+	c.defaultSecurity = opts.DefaultSecurity
+	c.target = opts.Target
+	c.diagnostics = swagger.diagnostics
+	if opts.Origins != nil {
+		c.origins = opts.Origins
+	}
+	api, err := c.swaggerToAPI(swagger)
+	if opts.Warn != nil {
+		for _, d := range c.diagnostics {
+			opts.Warn(d)
+		}
+	}
+	if err != nil {
+		return nil, swagger, nil, err
+	}
+	return c, swagger, api, nil
+}
+
+// Render writes the goa DSL of api. The code that does not compile is
+// reported as *SyntaxError.
+func Render(api *design.APIDefinition, w io.Writer, opts RenderOptions) error {
+	t, err := targetTemplate(opts.Target)
+	if err != nil {
+		return err
+	}
+	if opts.TemplateDir != "" {
+		if t, err = overrideTemplates(t, opts.TemplateDir); err != nil {
+			return err
+		}
+	}
+	switch opts.Layout {
+	case "", LayoutFile:
+		source, err := renderTemplate(t, "all", api, opts.Origins)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(source)
+		return err
+	case LayoutPackage:
+		if opts.Dir == "" {
+			return fmt.Errorf("no directory for layout %q", opts.Layout)
+		}
+		return writeDesignPackage(opts.Dir, t, api, opts.Origins)
+	default:
+		return fmt.Errorf("unsupported layout %q", opts.Layout)
+	}
+}
@@ -0,0 +1,384 @@
+package convert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+)
+
+const petstore = `swagger: "2.0"
+info: {title: petstore, version: "1.0"}
+paths:
+  /pets:
+    post:
+      operationId: create
+      parameters:
+      - name: body
+        in: body
+        schema:
+          type: object
+          properties:
+            name: {type: string, format: color}
+      responses:
+        "201":
+          description: Created
+          schema:
+            type: object
+            properties:
+              id: {type: integer}
+`
+
+func TestConvert(t *testing.T) {
+	var warnings []Diagnostic
+	origins := make(map[interface{}]string)
+	api, err := Convert(strings.NewReader(petstore), Options{
+		Naming:  Naming{PayloadSuffix: "Body"},
+		Warn:    func(d Diagnostic) { warnings = append(warnings, d) },
+		Origins: origins,
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	action := api.Resources["pets"].Actions["create"]
+	if action.Payload.TypeName != "CreateBody" {
+		t.Errorf("got payload %s, expected CreateBody", action.Payload.TypeName)
+	}
+	if mediaType := api.MediaTypeWithIdentifier(action.Responses["Created"].MediaType); mediaType == nil || mediaType.TypeName != "CreateCreatedMedia" {
+		t.Errorf("got media type %v, expected CreateCreatedMedia", mediaType)
+	}
+	if origins[action] != "/paths/~1pets/post" {
+		t.Errorf("got origin %q, expected /paths/~1pets/post", origins[action])
+	}
+	if len(warnings) != 1 || warnings[0].Pointer != "/paths/~1pets/post/parameters/0/schema/properties/name" {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	_, err = Convert(strings.NewReader(`{"swagger":"2.0","security":[{"undefined":[]}]}`), Options{})
+	if e, ok := err.(*Error); !ok || e.Pointer != "/security" {
+		t.Errorf("got error %#v, expected an error at /security", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	api, err := Convert(strings.NewReader(petstore), Options{})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	cases := map[string]struct {
+		target   string
+		expected string
+	}{
+		"default": {"", `. "github.com/goadesign/goa/design/apidsl"`},
+		"v3":      {TargetV3, `. "goa.design/goa/v3/dsl"`},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := Render(api, buf, RenderOptions{Target: tc.target}); err != nil {
+			t.Fatalf("%s: Render returned %s", k, err)
+		}
+		if !strings.Contains(buf.String(), tc.expected) {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, buf.String(), tc.expected)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "design")
+	if err != nil {
+		t.Fatalf("TempDir returned %s", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := Render(api, nil, RenderOptions{Layout: LayoutPackage, Dir: dir}); err != nil {
+		t.Fatalf("Render returned %s with the package layout", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pets_resource.go")); err != nil {
+		t.Errorf("resource file is not written: %s", err)
+	}
+	if err := Render(api, nil, RenderOptions{Layout: LayoutPackage}); err == nil {
+		t.Errorf("Render accepted the package layout without a directory")
+	}
+}
+
+func TestRenderTemplateDir(t *testing.T) {
+	api, err := Convert(strings.NewReader(petstore), Options{})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	for _, target := range []string{TargetV1, TargetV3} {
+		expected := new(bytes.Buffer)
+		if err := Render(api, expected, RenderOptions{Target: target}); err != nil {
+			t.Fatalf("%s: Render returned %s", target, err)
+		}
+		sources, err := Templates(target)
+		if err != nil {
+			t.Fatalf("%s: Templates returned %s", target, err)
+		}
+		dir, err := ioutil.TempDir("", "templates")
+		if err != nil {
+			t.Fatalf("TempDir returned %s", err)
+		}
+		defer os.RemoveAll(dir)
+		for name, source := range sources {
+			if err := ioutil.WriteFile(filepath.Join(dir, name+templateExt), []byte(source), 0644); err != nil {
+				t.Fatalf("WriteFile returned %s", err)
+			}
+		}
+		actual := new(bytes.Buffer)
+		if err := Render(api, actual, RenderOptions{Target: target, TemplateDir: dir}); err != nil {
+			t.Fatalf("%s: Render returned %s with the built-in templates", target, err)
+		}
+		if actual.String() != expected.String() {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", target, actual, expected)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, "title.tmpl"), []byte(`Title("custom")`), 0644); err != nil {
+			t.Fatalf("WriteFile returned %s", err)
+		}
+		actual.Reset()
+		if err := Render(api, actual, RenderOptions{Target: target, TemplateDir: dir}); err != nil {
+			t.Fatalf("%s: Render returned %s with an overridden template", target, err)
+		}
+		if !strings.Contains(actual.String(), `Title("custom")`) {
+			t.Errorf("%s: template is not overridden:\n%s", target, actual)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, "title.tmpl"), nil, 0644); err != nil {
+			t.Fatalf("WriteFile returned %s", err)
+		}
+		actual.Reset()
+		if err := Render(api, actual, RenderOptions{Target: target, TemplateDir: dir}); err != nil {
+			t.Fatalf("%s: Render returned %s with an empty template", target, err)
+		}
+		if strings.Contains(actual.String(), `Title(`) {
+			t.Errorf("%s: template is not overridden with an empty template:\n%s", target, actual)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, "unknown.tmpl"), nil, 0644); err != nil {
+			t.Fatalf("WriteFile returned %s", err)
+		}
+		if err := Render(api, actual, RenderOptions{Target: target, TemplateDir: dir}); err == nil {
+			t.Errorf("%s: Render accepted an unknown template", target)
+		}
+	}
+}
+
+func TestConvertOptions(t *testing.T) {
+	document := `swagger: "2.0"
+securityDefinitions:
+  basic: {type: basic}
+paths:
+  /pets:
+    get:
+      operationId: list
+      responses:
+        "200": {description: OK, schema: {$ref: "#/definitions/Pet"}}
+    delete:
+      operationId: purge
+      tags: [internal]
+  /internal/health:
+    get:
+      operationId: health
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+`
+	api, err := Convert(strings.NewReader(document), Options{
+		TypeNames:       map[string]string{"Pet": "Animal"},
+		IgnorePaths:     []string{"/internal/*"},
+		IgnoreTags:      []string{"internal"},
+		DefaultSecurity: "basic",
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	if len(api.Resources) != 1 || len(api.Resources["pets"].Actions) != 1 || api.Resources["pets"].Actions["list"] == nil {
+		t.Errorf("unexpected resources %v", api.Resources)
+	}
+	if api.Types["Pet"].TypeName != "Animal" {
+		t.Errorf("got type %s, expected Animal", api.Types["Pet"].TypeName)
+	}
+	if mediaType := api.MediaTypes["application/vnd.animal"]; mediaType == nil || mediaType.TypeName != "AnimalMedia" {
+		t.Errorf("unexpected media types %v", api.MediaTypes)
+	}
+	if api.Security == nil || api.Security.Scheme.SchemeName != "basic" {
+		t.Errorf("got security %v, expected basic", api.Security)
+	}
+
+	document += "  Cat: {type: object, properties: {name: {type: string}}}\n"
+	_, err = Convert(strings.NewReader(document), Options{
+		TypeNames: map[string]string{"Pet": "Animal", "Cat": "Animal"},
+	})
+	if e, ok := err.(*Error); !ok || e.Pointer != "/definitions/Pet" {
+		t.Errorf("got error %#v, expected an error at /definitions/Pet", err)
+	}
+}
+
+func TestConvertGrouping(t *testing.T) {
+	document := `swagger: "2.0"
+tags:
+- {name: animals, description: Animals of the store}
+paths:
+  /pets:
+    get: {operationId: list, tags: [animals], x-goa-resource: pets}
+  /pets/{id}:
+    get: {operationId: show, tags: [animals], x-goa-resource: pets}
+    delete: {operationId: remove, tags: [animals]}
+  /pets/{id}/owner:
+    get: {operationId: owner, x-goa-resource: owners}
+  /birds/{id}:
+    get: {operationId: bird, tags: [animals]}
+`
+	type resource struct {
+		basePath  string
+		canonical string
+		routes    map[string]string
+	}
+	cases := map[string]struct {
+		grouping string
+		expected map[string]resource
+	}{
+		"path": {GroupByPath, map[string]resource{
+			"pets":  {"/pets", "show", map[string]string{"list": "", "show": "/:id", "remove": "/:id", "owner": "/:id/owner"}},
+			"birds": {"/birds", "bird", map[string]string{"bird": "/:id"}},
+		}},
+		"tag": {GroupByTag, map[string]resource{
+			"animals": {"", "", map[string]string{"list": "/pets", "show": "/pets/:id", "remove": "/pets/:id", "bird": "/birds/:id"}},
+			"pets":    {"/pets", "", map[string]string{"owner": "/:id/owner"}},
+		}},
+		"extension": {GroupByExtension, map[string]resource{
+			"pets":   {"/pets", "show", map[string]string{"list": "", "show": "/:id", "remove": "/:id"}},
+			"owners": {"/pets", "", map[string]string{"owner": "/:id/owner"}},
+			"birds":  {"/birds", "bird", map[string]string{"bird": "/:id"}},
+		}},
+	}
+	for k, tc := range cases {
+		api, err := Convert(strings.NewReader(document), Options{Grouping: tc.grouping})
+		if err != nil {
+			t.Fatalf("%s: Convert returned %s", k, err)
+		}
+		actual := make(map[string]resource)
+		for name, r := range api.Resources {
+			routes := make(map[string]string)
+			for _, action := range r.Actions {
+				routes[action.Name] = action.Routes[0].Path
+			}
+			actual[name] = resource{r.BasePath, r.CanonicalActionName, routes}
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+		if r, ok := api.Resources["animals"]; ok && r.Description != "Animals of the store" {
+			t.Errorf("%s: got description %q, expected the description of the tag", k, r.Description)
+		}
+	}
+
+	if _, err := Convert(strings.NewReader(document), Options{Grouping: "unknown"}); err == nil {
+		t.Errorf("Convert accepted an unknown grouping")
+	}
+}
+
+func TestConvertExtensions(t *testing.T) {
+	document := `swagger: "2.0"
+x-goa-metadata: {swagger:generate: false}
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      x-goa-resource: pets
+      x-goa-action: show
+      x-goa-metadata: {swagger:summary: [Show, pet]}
+      responses:
+        "200":
+          description: OK
+          x-goa-view: {name: tiny, attributes: [name, color]}
+          schema: {$ref: "#/definitions/Pet"}
+  /pets/{id}/toys:
+    post:
+      operationId: addToy
+      x-goa-resource: toys
+      x-goa-parent: pets
+      parameters:
+      - {name: body, in: body, schema: {type: object, x-goa-type-name: NewToy, properties: {name: {type: string}}}}
+      responses:
+        "201": {description: Created}
+  /stores:
+    get:
+      operationId: listStores
+      x-goa-action: ""
+      x-goa-parent: stores
+      responses:
+        "200": {description: OK, x-goa-view: full}
+definitions:
+  Pet:
+    type: object
+    x-goa-type-name: Animal
+    properties:
+      name: {type: string, x-goa-metadata: {struct:tag:json: [name, omitempty]}}
+      age: {type: integer, x-goa-metadata: [age]}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Grouping: GroupByExtension,
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	if !reflect.DeepEqual(api.Metadata["swagger:generate"], []string{"false"}) {
+		t.Errorf("got API metadata %v", api.Metadata)
+	}
+	show := api.Resources["pets"].Actions["show"]
+	if show == nil {
+		t.Fatalf("unexpected actions %v", api.Resources["pets"].Actions)
+	}
+	if !reflect.DeepEqual(show.Metadata["swagger:summary"], []string{"Show", "pet"}) {
+		t.Errorf("got action metadata %v", show.Metadata)
+	}
+	if api.Types["Pet"].TypeName != "Animal" {
+		t.Errorf("got type %s, expected Animal", api.Types["Pet"].TypeName)
+	}
+	name := api.Types["Pet"].Type.ToObject()["name"]
+	if !reflect.DeepEqual(name.Metadata["struct:tag:json"], []string{"name", "omitempty"}) {
+		t.Errorf("got attribute metadata %v", name.Metadata)
+	}
+	response := show.Responses["OK"]
+	if response.ViewName != "tiny" {
+		t.Errorf("got view %q, expected tiny", response.ViewName)
+	}
+	mediaType := api.MediaTypes["application/vnd.animal"]
+	if view := mediaType.Views["tiny"]; view == nil || len(view.Type.ToObject()) != 1 || view.Type.ToObject()["name"] == nil || view.Type.ToObject()["name"].Type != design.String {
+		t.Errorf("unexpected views %v", mediaType.Views)
+	}
+	toys := api.Resources["toys"]
+	if toys.ParentName != "pets" || toys.BasePath != "/toys" || toys.Actions["addToy"].Routes[0].Path != "" {
+		t.Errorf("got parent %q and base path %q, expected pets and /toys", toys.ParentName, toys.BasePath)
+	}
+	if toys.Actions["addToy"].Payload.TypeName != "NewToy" {
+		t.Errorf("got payload %s, expected NewToy", toys.Actions["addToy"].Payload.TypeName)
+	}
+	if api.Resources["stores"].ParentName != "" || api.Resources["stores"].Actions["listStores"] == nil {
+		t.Errorf("unexpected resource %v", api.Resources["stores"])
+	}
+
+	expected := []string{
+		`warning: /definitions/Pet/properties/age/x-goa-metadata: x-goa-metadata must be an object and is ignored`,
+		`warning: /paths/~1pets~1{id}/get/responses/200/x-goa-view/attributes/1: attribute "color" of view "tiny" is not defined by the media type and is ignored`,
+		`warning: /paths/~1stores/get/x-goa-action: x-goa-action must be a non-empty string and is ignored`,
+		`warning: /paths/~1stores/get/responses/200/x-goa-view: x-goa-view is ignored because the response has no media type`,
+		`warning: /paths/~1stores/get/x-goa-parent: parent resource "stores" refers to resource "stores" circularly and is ignored`,
+	}
+	sort.Strings(warnings)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expected, "\n"))
+	}
+}
@@ -0,0 +1,52 @@
+package convert
+
+import "strings"
+
+// Severity is the severity of a diagnostic.
+type Severity string
+
+// Severities of diagnostics.
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Diagnostic is a problem found while converting a swagger document. Pointer
+// is the JSON pointer of the node involved, which is empty if the problem is
+// not specific to a node.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Pointer  string   `json:"pointer,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	if d.Pointer == "" {
+		return string(d.Severity) + ": " + d.Message
+	}
+	return string(d.Severity) + ": " + d.Pointer + ": " + d.Message
+}
+
+// Error is an error at a node of a swagger document. Pointer is the JSON
+// pointer of the node, which is empty if the error is not specific to a
+// node.
+type Error struct {
+	Pointer string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+	return e.Pointer + ": " + e.Message
+}
+
+// jsonPointer returns the JSON pointer made of tokens.
+func jsonPointer(tokens []string) string {
+	var pointer string
+	for _, token := range tokens {
+		pointer += "/" + strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+	}
+	return pointer
+}
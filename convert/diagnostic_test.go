@@ -0,0 +1,68 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+func TestJSONPointer(t *testing.T) {
+	actual := jsonPointer([]string{"paths", "/users/{id}", "get", "a~b"})
+	if expected := "/paths/~1users~1{id}/get/a~0b"; actual != expected {
+		t.Errorf("got %s, expected %s", actual, expected)
+	}
+}
+
+func TestSwaggerToAPIDiagnostics(t *testing.T) {
+	swagger := swaggerDocument{
+		Swagger: genswagger.Swagger{
+			Paths: map[string]interface{}{
+				"/users": map[string]interface{}{
+					"get": map[string]interface{}{
+						"operationId": "list",
+						"parameters": []interface{}{
+							map[string]interface{}{"name": "ids", "in": "query", "type": "array", "items": map[string]interface{}{"type": "string"}, "collectionFormat": "pipes"},
+						},
+						"responses": map[string]interface{}{
+							"200":     map[string]interface{}{"description": "OK"},
+							"default": map[string]interface{}{"description": "Error"},
+						},
+					},
+				},
+			},
+			Definitions: map[string]*genschema.JSONSchema{
+				"User": &genschema.JSONSchema{
+					Type: genschema.Object,
+					Properties: map[string]*genschema.JSONSchema{
+						"id": &genschema.JSONSchema{Type: genschema.String, Format: "color"},
+					},
+				},
+			},
+		},
+	}
+	c := newConverter(swagger.Swagger)
+	_, err := c.swaggerToAPI(swagger)
+	ds := c.diagnostics
+	if err != nil {
+		t.Fatalf("swaggerToAPI returned %s", err)
+	}
+	expected := []Diagnostic{
+		{Severity: SeverityWarning, Pointer: "/definitions/User/properties/id", Message: `format "color" is not supported and is ignored`},
+		{Severity: SeverityWarning, Pointer: "/paths/~1users/get/parameters/0", Message: `collection format "pipes" is not supported, goa uses csv`},
+		{Severity: SeverityWarning, Pointer: "/paths/~1users/get/responses/default", Message: `response "default" is not supported and is ignored`},
+	}
+	if !reflect.DeepEqual(ds, expected) {
+		t.Errorf("\ngot:\n%v\nexpected:\n%v", ds, expected)
+	}
+
+	swagger.Definitions["User"].Properties["group"] = &genschema.JSONSchema{Ref: "#/definitions/Group"}
+	_, err = newConverter(swagger.Swagger).swaggerToAPI(swagger)
+	if err == nil {
+		t.Fatalf("swaggerToAPI accepted an unresolved reference")
+	}
+	if e, ok := err.(*Error); !ok || e.Pointer != "/definitions/User/properties/group" {
+		t.Errorf("got error %#v, expected an error at /definitions/User/properties/group", err)
+	}
+}
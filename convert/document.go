@@ -0,0 +1,79 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Formats of swagger documents.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// detectFormat returns the format of a swagger document. The extension of
+// path takes precedence over the content of data.
+func detectFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// decodeDocument decodes data in format into generic values. YAML documents
+// are converted to the same values as the equivalent JSON documents.
+func decodeDocument(data []byte, format string) (interface{}, error) {
+	var document interface{}
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		return document, nil
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		return yamlToJSON(document), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// yamlToJSON converts the values decoded from YAML to the values decoded from
+// JSON. Map keys are converted to strings.
+func yamlToJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[fmt.Sprint(k)] = yamlToJSON(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, v := range t {
+			s[i] = yamlToJSON(v)
+		}
+		return s
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	default:
+		return v
+	}
+}
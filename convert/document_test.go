@@ -0,0 +1,97 @@
+package convert
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	cases := map[string]struct {
+		path     string
+		data     string
+		expected string
+	}{
+		"with json extension": {
+			path:     "swagger.json",
+			data:     `swagger: "2.0"`,
+			expected: FormatJSON,
+		},
+		"with yaml extension": {
+			path:     "swagger.YAML",
+			data:     `{"swagger": "2.0"}`,
+			expected: FormatYAML,
+		},
+		"with yml extension": {
+			path:     "swagger.yml",
+			expected: FormatYAML,
+		},
+		"with json content": {
+			path:     "swagger",
+			data:     "\n  {\"swagger\": \"2.0\"}",
+			expected: FormatJSON,
+		},
+		"with yaml content": {
+			path:     "swagger",
+			data:     `swagger: "2.0"`,
+			expected: FormatYAML,
+		},
+	}
+	for k, tc := range cases {
+		actual := detectFormat(tc.path, []byte(tc.data))
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestDecodeDocument(t *testing.T) {
+	cases := map[string]struct {
+		data     string
+		expected string
+	}{
+		"with non-string keys": {
+			data: `
+responses:
+  200:
+    description: OK
+  true: 1
+`,
+			expected: `{"responses":{"200":{"description":"OK"},"true":1}}`,
+		},
+		"with anchors": {
+			data: `
+definitions:
+  Pet: &pet
+    type: object
+  Dog: *pet
+`,
+			expected: `{"definitions":{"Pet":{"type":"object"},"Dog":{"type":"object"}}}`,
+		},
+		"with multi-line description": {
+			data: `
+info:
+  description: |
+    first line
+    second line
+  tags: [a, b]
+  minimum: 1.5
+`,
+			expected: `{"info":{"description":"first line\nsecond line\n","tags":["a","b"],"minimum":1.5}}`,
+		},
+	}
+	for k, tc := range cases {
+		actual, err := decodeDocument([]byte(tc.data), FormatYAML)
+		if err != nil {
+			t.Fatalf("%s: decodeDocument returned %s", k, err)
+		}
+		expected, err := decodeDocument([]byte(tc.expected), FormatJSON)
+		if err != nil {
+			t.Fatalf("%s: decodeDocument returned %s", k, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			a, _ := json.Marshal(actual)
+			t.Errorf("%s: got %s, expected %s", k, a, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package convert
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Vendor extensions that control the conversion.
+const (
+	// extensionResource is the name of the resource of an operation when
+	// operations are grouped by GroupByExtension.
+	extensionResource = "x-goa-resource"
+	// extensionAction is the name of the action of an operation, which
+	// replaces the operationId.
+	extensionAction = "x-goa-action"
+	// extensionTypeName is the name of the type of a definition, of an
+	// inline payload or of the media type of a response.
+	extensionTypeName = "x-goa-type-name"
+	// extensionView is the view of the media type of a response, either a
+	// name or an object with the name and the attributes of the view.
+	extensionView = "x-goa-view"
+	// extensionMetadata is the metadata of the API, of an action or of an
+	// attribute, an object of strings or lists of strings.
+	extensionMetadata = "x-goa-metadata"
+	// extensionParent is the name of the parent resource of the resource
+	// of an operation.
+	extensionParent = "x-goa-parent"
+	// extensionExample is the example of a non-body parameter, of a header
+	// or of a schema that has no example.
+	extensionExample = "x-example"
+	// extensionNoExamples disables the generation of examples by goa when it
+	// is true on the root.
+	extensionNoExamples = "x-no-examples"
+)
+
+// node returns the node being converted, as decoded from the document, or
+// nil if the pointer does not refer to a node of the document. The decoded
+// swagger does not hold vendor extensions nor the keywords that goa has no
+// equivalent of, so they are read from the node.
+func (c *converter) node() interface{} {
+	node := c.document
+	for _, token := range c.pointer {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			node = n[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil
+			}
+			node = n[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// extension returns the value of the vendor extension name of the node being
+// converted, or nil if the node has no such extension.
+func (c *converter) extension(name string) interface{} {
+	object, _ := c.node().(map[string]interface{})
+	return object[name]
+}
+
+// stringExtension returns the value of the vendor extension name of the node
+// being converted. Values other than non-empty strings are warned about and
+// ignored.
+func (c *converter) stringExtension(name string) (string, bool) {
+	v := c.extension(name)
+	if v == nil {
+		return "", false
+	}
+	if s, ok := v.(string); ok && s != "" {
+		return s, true
+	}
+	defer c.at(name)()
+	c.warnf("%s must be a non-empty string and is ignored", name)
+	return "", false
+}
+
+// metadataExtension returns the metadata of the node being converted, set by
+// the x-goa-metadata vendor extension. It returns nil if there is no metadata.
+func (c *converter) metadataExtension() dslengine.MetadataDefinition {
+	v := c.extension(extensionMetadata)
+	if v == nil {
+		return nil
+	}
+	defer c.at(extensionMetadata)()
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		c.warnf("%s must be an object and is ignored", extensionMetadata)
+		return nil
+	}
+	metadata := make(dslengine.MetadataDefinition)
+	for name, value := range object {
+		switch value := value.(type) {
+		case []interface{}:
+			var values []string
+			for _, v := range value {
+				s, ok := scalarString(v)
+				if !ok {
+					back := c.at(name)
+					c.warnf("metadata %q must be a string or a list of strings and is ignored", name)
+					back()
+					values = nil
+					break
+				}
+				values = append(values, s)
+			}
+			if values != nil {
+				metadata[name] = values
+			}
+		default:
+			s, ok := scalarString(value)
+			if !ok {
+				back := c.at(name)
+				c.warnf("metadata %q must be a string or a list of strings and is ignored", name)
+				back()
+				continue
+			}
+			metadata[name] = []string{s}
+		}
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
+
+// scalarString returns the string representation of v if it is a string, a
+// number or a boolean.
+func scalarString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64, bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
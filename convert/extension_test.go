@@ -0,0 +1,81 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+func TestMetadataExtension(t *testing.T) {
+	cases := map[string]struct {
+		metadata interface{}
+		expected dslengine.MetadataDefinition
+		warnings int
+	}{
+		"strings": {
+			metadata: map[string]interface{}{"struct:tag:json": "name", "swagger:tag": []interface{}{"pets", "store"}},
+			expected: dslengine.MetadataDefinition{"struct:tag:json": {"name"}, "swagger:tag": {"pets", "store"}},
+		},
+		"scalars": {
+			metadata: map[string]interface{}{"swagger:generate": false, "order": []interface{}{float64(1), "2"}},
+			expected: dslengine.MetadataDefinition{"swagger:generate": {"false"}, "order": {"1", "2"}},
+		},
+		"invalid values": {
+			metadata: map[string]interface{}{"valid": "yes", "object": map[string]interface{}{}, "list": []interface{}{"a", nil}},
+			expected: dslengine.MetadataDefinition{"valid": {"yes"}},
+			warnings: 2,
+		},
+		"not an object": {
+			metadata: []interface{}{"name"},
+			warnings: 1,
+		},
+		"without metadata": {},
+	}
+	for k, tc := range cases {
+		c := newConverter(genswagger.Swagger{})
+		node := map[string]interface{}{}
+		if tc.metadata != nil {
+			node[extensionMetadata] = tc.metadata
+		}
+		c.document = map[string]interface{}{"definitions": map[string]interface{}{"Pet": node}}
+		c.pointer = []string{"definitions", "Pet"}
+		actual := c.metadataExtension()
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+		if len(c.diagnostics) != tc.warnings {
+			t.Errorf("%s: got warnings %v, expected %d warnings", k, c.diagnostics, tc.warnings)
+		}
+	}
+}
+
+func TestStringExtension(t *testing.T) {
+	cases := map[string]struct {
+		value    interface{}
+		expected string
+		ok       bool
+	}{
+		"string":       {"show", "show", true},
+		"empty string": {"", "", false},
+		"number":       {float64(1), "", false},
+		"missing":      {nil, "", false},
+	}
+	for k, tc := range cases {
+		c := newConverter(genswagger.Swagger{})
+		operation := map[string]interface{}{}
+		if tc.value != nil {
+			operation[extensionAction] = tc.value
+		}
+		c.document = map[string]interface{}{"paths": map[string]interface{}{"/pets": map[string]interface{}{"get": operation}}}
+		c.pointer = []string{"paths", "/pets", "get"}
+		actual, ok := c.stringExtension(extensionAction)
+		if actual != tc.expected || ok != tc.ok {
+			t.Errorf("%s: got %q, %t, expected %q, %t", k, actual, ok, tc.expected, tc.ok)
+		}
+		if warned := len(c.diagnostics) > 0; warned != (tc.value != nil && !tc.ok) {
+			t.Errorf("%s: got warnings %v", k, c.diagnostics)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+// FormatMapping is the conversion of the schemas of a format.
+type FormatMapping struct {
+	// Type is the goa type of the values: Boolean, Integer, Number, String,
+	// DateTime, UUID, File or Any. The type of the schema is kept if it is
+	// empty.
+	Type string
+	// Validation is the format that goa validates the values with, such as
+	// email. The values are not validated if it is empty.
+	Validation string
+}
+
+// defaultFormats holds the conversion of the formats defined by swagger and
+// of the formats that goa validates.
+var defaultFormats = map[string]FormatMapping{
+	"int32":     {Type: "Integer"},
+	"int64":     {Type: "Integer"},
+	"float":     {Type: "Number"},
+	"double":    {Type: "Number"},
+	"byte":      {Type: "String"},
+	"binary":    {Type: "File"},
+	"date":      {Type: "String"},
+	"date-time": {Type: "DateTime"},
+	"password":  {Type: "String"},
+	"uuid":      {Type: "UUID"},
+	"cidr":      {Type: "String", Validation: "cidr"},
+	"email":     {Type: "String", Validation: "email"},
+	"hostname":  {Type: "String", Validation: "hostname"},
+	"ip":        {Type: "String", Validation: "ip"},
+	"ipv4":      {Type: "String", Validation: "ipv4"},
+	"ipv6":      {Type: "String", Validation: "ipv6"},
+	"mac":       {Type: "String", Validation: "mac"},
+	"regexp":    {Type: "String", Validation: "regexp"},
+	"rfc1123":   {Type: "String", Validation: "rfc1123"},
+	"uri":       {Type: "String", Validation: "uri"},
+}
+
+// formatType is a type that formats map to.
+type formatType struct {
+	dataType design.DataType
+	// schemaTypes are the types of the schemas whose values the type can
+	// hold. Schemas of other types keep their type.
+	schemaTypes []genschema.JSONType
+}
+
+// formatTypes maps the names of the types of FormatMapping to goa types.
+var formatTypes = map[string]formatType{
+	"Boolean":  {design.Boolean, []genschema.JSONType{genschema.Boolean}},
+	"Integer":  {design.Integer, []genschema.JSONType{genschema.Integer, genschema.Number}},
+	"Number":   {design.Number, []genschema.JSONType{genschema.Integer, genschema.Number}},
+	"String":   {design.String, []genschema.JSONType{genschema.String}},
+	"DateTime": {design.DateTime, []genschema.JSONType{genschema.String}},
+	"UUID":     {design.UUID, []genschema.JSONType{genschema.String}},
+	"File":     {design.File, []genschema.JSONType{genschema.String, genschema.File}},
+	"Any":      {design.Any, nil},
+}
+
+// validationFormats is the set of formats that goa can validate.
+var validationFormats = map[string]bool{
+	"cidr":      true,
+	"date-time": true,
+	"email":     true,
+	"hostname":  true,
+	"ip":        true,
+	"ipv4":      true,
+	"ipv6":      true,
+	"mac":       true,
+	"regexp":    true,
+	"rfc1123":   true,
+	"uri":       true,
+}
+
+// mergeFormats returns the default formats overridden by formats. It returns
+// an error if a mapping has an unknown type or validation.
+func mergeFormats(formats map[string]FormatMapping) (map[string]FormatMapping, error) {
+	merged := make(map[string]FormatMapping, len(defaultFormats)+len(formats))
+	for name, m := range defaultFormats {
+		merged[name] = m
+	}
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m := formats[name]
+		if _, ok := formatTypes[m.Type]; m.Type != "" && !ok {
+			return nil, fmt.Errorf("unsupported type %q of format %q", m.Type, name)
+		}
+		if m.Validation != "" && !validationFormats[m.Validation] {
+			return nil, fmt.Errorf("unsupported validation %q of format %q", m.Validation, name)
+		}
+		merged[name] = m
+	}
+	return merged, nil
+}
+
+// applyFormat sets the type and the format validation of attribute, converted
+// from schema, from the mapping of the format of schema. The type is kept if
+// the type of the mapping cannot hold the values of the schema.
+func (c *converter) applyFormat(attribute *design.AttributeDefinition, schema *genschema.JSONSchema) {
+	if schema.Format == "" {
+		return
+	}
+	m, ok := c.formats[schema.Format]
+	if !ok {
+		c.warnf("format %q is not supported and is ignored", schema.Format)
+		return
+	}
+	if _, ok := attribute.Type.(design.Primitive); !ok {
+		return
+	}
+	if t, ok := formatTypes[m.Type]; ok {
+		if schema.Type == "" || t.schemaTypes == nil || containsJSONType(t.schemaTypes, schema.Type) {
+			attribute.Type = t.dataType
+		} else {
+			c.warnf("format %q of a schema of type %s cannot be converted to %s, the type of the schema is kept", schema.Format, schema.Type, m.Type)
+		}
+	}
+	if m.Validation != "" {
+		if attribute.Validation == nil {
+			attribute.Validation = &dslengine.ValidationDefinition{}
+		}
+		attribute.Validation.Format = m.Validation
+	}
+}
+
+// containsJSONType returns true if types contains t.
+func containsJSONType(types []genschema.JSONType, t genschema.JSONType) bool {
+	for _, x := range types {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package convert
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+)
+
+func TestMergeFormats(t *testing.T) {
+	cases := map[string]struct {
+		formats  map[string]FormatMapping
+		name     string
+		expected FormatMapping
+		err      string
+	}{
+		"default": {
+			name:     "date-time",
+			expected: FormatMapping{Type: "DateTime"},
+		},
+		"custom": {
+			formats:  map[string]FormatMapping{"email-address": {Type: "String", Validation: "email"}},
+			name:     "email-address",
+			expected: FormatMapping{Type: "String", Validation: "email"},
+		},
+		"override": {
+			formats:  map[string]FormatMapping{"date": {Type: "DateTime", Validation: "date-time"}},
+			name:     "date",
+			expected: FormatMapping{Type: "DateTime", Validation: "date-time"},
+		},
+		"unsupported type": {
+			formats: map[string]FormatMapping{"money": {Type: "Decimal"}},
+			err:     `unsupported type "Decimal" of format "money"`,
+		},
+		"unsupported validation": {
+			formats: map[string]FormatMapping{"phone": {Type: "String", Validation: "phone"}},
+			err:     `unsupported validation "phone" of format "phone"`,
+		},
+	}
+	for k, tc := range cases {
+		formats, err := mergeFormats(tc.formats)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: got error %v, expected %s", k, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: mergeFormats returned %s", k, err)
+		}
+		if actual := formats[tc.name]; actual != tc.expected {
+			t.Errorf("%s: got %+v, expected %+v", k, actual, tc.expected)
+		}
+	}
+	if _, ok := defaultFormats["email-address"]; ok {
+		t.Errorf("mergeFormats modified the default formats")
+	}
+}
+
+func TestConvertFormats(t *testing.T) {
+	document := `swagger: "2.0"
+paths: {}
+definitions:
+  Pet:
+    type: object
+    properties:
+      id: {type: string, format: uuid}
+      born: {type: string, format: date-time}
+      weight: {type: number, format: float}
+      count: {type: integer, format: int64}
+      photo: {type: string, format: binary}
+      owner: {type: string, format: email}
+      contact: {type: string, format: email-address}
+      code: {type: string, format: int64}
+      color: {type: string, format: color}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Formats: map[string]FormatMapping{"email-address": {Type: "String", Validation: "email"}},
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	pet := api.Types["Pet"].Type.ToObject()
+	expected := map[string]struct {
+		dataType design.DataType
+		format   string
+	}{
+		"id":      {design.UUID, ""},
+		"born":    {design.DateTime, ""},
+		"weight":  {design.Number, ""},
+		"count":   {design.Integer, ""},
+		"photo":   {design.File, ""},
+		"owner":   {design.String, "email"},
+		"contact": {design.String, "email"},
+		"code":    {design.String, ""},
+		"color":   {design.String, ""},
+	}
+	for name, e := range expected {
+		attribute := pet[name]
+		var format string
+		if attribute.Validation != nil {
+			format = attribute.Validation.Format
+		}
+		if attribute.Type != e.dataType || format != e.format {
+			t.Errorf("%s: got %s with format %q, expected %s with format %q", name, attribute.Type.Name(), format, e.dataType.Name(), e.format)
+		}
+	}
+	expectedWarnings := []string{
+		`warning: /definitions/Pet/properties/code: format "int64" of a schema of type string cannot be converted to Integer, the type of the schema is kept`,
+		`warning: /definitions/Pet/properties/color: format "color" is not supported and is ignored`,
+	}
+	sort.Strings(warnings)
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expectedWarnings, "\n"))
+	}
+
+	_, err = Convert(strings.NewReader(document), Options{
+		Formats: map[string]FormatMapping{"color": {Type: "Color"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unsupported type "Color" of format "color"`) {
+		t.Errorf("got error %v, expected an unsupported type", err)
+	}
+}
@@ -0,0 +1,79 @@
+package convert
+
+import (
+	"github.com/goadesign/goa/design"
+)
+
+// namedKeys are the keys of the objects whose entries are named by the
+// document, such as properties, rather than by the specification.
+var namedKeys = map[string]bool{
+	"definitions":         true,
+	"headers":             true,
+	"parameters":          true,
+	"paths":               true,
+	"properties":          true,
+	"responses":           true,
+	"securityDefinitions": true,
+}
+
+// boolAdditionalProperties returns a copy of node, a document decoded into
+// generic values, whose schemas of additionalProperties are replaced with
+// true. genschema.JSONSchema only holds booleans, so the schemas are read
+// from the document when the schemas are converted. named is true if node
+// is an object whose entries are named by the document.
+func boolAdditionalProperties(node interface{}, named bool) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			if !named && k == "additionalProperties" {
+				if _, ok := v.(bool); !ok {
+					v = true
+				}
+				copied[k] = v
+				continue
+			}
+			copied[k] = boolAdditionalProperties(v, !named && namedKeys[k])
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(n))
+		for i, v := range n {
+			copied[i] = boolAdditionalProperties(v, false)
+		}
+		return copied
+	}
+	return node
+}
+
+// additionalPropertiesToHash converts the additional properties of the schema
+// being converted to a hash of strings. The values of additional properties
+// allowed with true are of any type.
+func (c *converter) additionalPropertiesToHash() (*design.Hash, error) {
+	defer c.at("additionalProperties")()
+	value := &design.AttributeDefinition{
+		Type: design.Any,
+	}
+	if v, ok := c.node().(map[string]interface{}); ok {
+		schema, err := decodeSchema(v)
+		if err != nil {
+			return nil, c.errorf("invalid schema: %s", err)
+		}
+		if value, err = c.schemaToAttribute(schema); err != nil {
+			return nil, err
+		}
+		if _, ok := value.Type.(design.Object); ok {
+			c.warnf("inline objects as values of additional properties are not supported and are converted to Any, refer to a definition instead")
+			value = &design.AttributeDefinition{
+				Type:        design.Any,
+				Description: value.Description,
+			}
+		}
+	}
+	return &design.Hash{
+		KeyType: &design.AttributeDefinition{
+			Type: design.String,
+		},
+		ElemType: value,
+	}, nil
+}
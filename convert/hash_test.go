@@ -0,0 +1,96 @@
+package convert
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+)
+
+func TestBoolAdditionalProperties(t *testing.T) {
+	schema := map[string]interface{}{"type": "integer"}
+	cases := map[string]struct {
+		node     interface{}
+		expected interface{}
+	}{
+		"schema": {
+			map[string]interface{}{"additionalProperties": schema},
+			map[string]interface{}{"additionalProperties": true},
+		},
+		"boolean": {
+			map[string]interface{}{"additionalProperties": false},
+			map[string]interface{}{"additionalProperties": false},
+		},
+		"nested": {
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"additionalProperties": schema}}},
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"additionalProperties": true}}},
+		},
+		"property named additionalProperties": {
+			map[string]interface{}{"properties": map[string]interface{}{"additionalProperties": schema}},
+			map[string]interface{}{"properties": map[string]interface{}{"additionalProperties": schema}},
+		},
+	}
+	for k, tc := range cases {
+		actual := boolAdditionalProperties(tc.node, false)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+	if _, ok := schema["additionalProperties"]; ok {
+		t.Errorf("the node is modified")
+	}
+}
+
+func TestConvertAdditionalProperties(t *testing.T) {
+	document := `swagger: "2.0"
+paths: {}
+definitions:
+  Pet:
+    type: object
+    properties:
+      counts: {type: object, additionalProperties: {type: integer}}
+      labels: {type: object, additionalProperties: true}
+      owners: {type: object, additionalProperties: {$ref: "#/definitions/Owner"}}
+      tags: {type: object, additionalProperties: {type: object, properties: {name: {type: string}}}}
+  Owner:
+    type: object
+    properties:
+      name: {type: string}
+    additionalProperties: {type: string}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	pet := api.Types["Pet"].Type.ToObject()
+	expected := map[string]design.DataType{
+		"counts": design.Integer,
+		"labels": design.Any,
+		"owners": api.Types["Owner"],
+		"tags":   design.Any,
+	}
+	for name, elem := range expected {
+		hash, ok := pet[name].Type.(*design.Hash)
+		if !ok || hash.KeyType.Type != design.String || hash.ElemType.Type != elem {
+			t.Errorf("%s: got %#v, expected a hash of %s", name, pet[name].Type, elem.Name())
+		}
+	}
+	if owner := api.Types["Owner"].Type.ToObject(); len(owner) != 1 || owner["name"] == nil {
+		t.Errorf("unexpected attributes of Owner %v", owner)
+	}
+	expectedWarnings := []string{
+		"warning: /definitions/Owner/additionalProperties: additional properties cannot be combined with fixed properties, the open-ended keys are lost",
+		"warning: /definitions/Pet/properties/tags/additionalProperties: inline objects as values of additional properties are not supported and are converted to Any, refer to a definition instead",
+	}
+	sort.Strings(warnings)
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expectedWarnings, "\n"))
+	}
+}
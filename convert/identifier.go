@@ -0,0 +1,103 @@
+package convert
+
+import "github.com/goadesign/goa/goagen/codegen"
+
+// dslExports lists by package the identifiers exported by the packages that
+// the generated designs dot-import. The variables that define types and media
+// types cannot be named after them.
+var dslExports = func() map[string][]string {
+	exports := map[string][]string{
+		"github.com/goadesign/goa/design": {
+			"APIDefinition", "APIKeySecurityKind", "ActionDefinition", "Any",
+			"AnyKind", "Array", "ArrayKind", "ArrayVal", "AttributeDefinition",
+			"BasicAuthSecurityKind", "Boolean", "BooleanKind", "CORSDefinition",
+			"CanonicalIdentifier", "ContactDefinition", "ContainerDefinition",
+			"DataStructure", "DataType", "DateTime", "DateTimeKind", "Design",
+			"DocsDefinition", "Dup", "DupAtt", "EncodingDefinition", "ErrorMedia",
+			"ErrorMediaIdentifier", "ExtractWildcards", "File", "FileKind",
+			"FileServerDefinition", "GeneratedMediaTypes", "Hash", "HashKind",
+			"HashVal", "HasFile", "Integer", "IntegerKind", "JWTSecurityKind", "Kind",
+			"LicenseDefinition", "LinkDefinition", "MediaTypeDefinition",
+			"MediaTypeKind", "MediaTypeRoot", "NoSecurityKind", "Number",
+			"NumberKind", "OAuth2SecurityKind", "Object", "ObjectKind", "Primitive",
+			"ProjectedMediaTypes", "ResourceDefinition", "ResponseDefinition",
+			"ResponseTemplateDefinition", "RouteDefinition", "SecurityDefinition",
+			"SecuritySchemeDefinition", "SecuritySchemeKind", "String", "StringKind",
+			"UUID", "UUIDKind", "UserTypeDefinition", "UserTypeKind",
+			"ViewDefinition", "WildcardRegex",
+		},
+		"github.com/goadesign/goa/design/apidsl": {
+			"API", "APIKeySecurity", "AccessCodeFlow", "Action", "ApplicationFlow",
+			"ArrayOf", "Attribute", "Attributes", "BasePath", "BasicAuthSecurity",
+			"CONNECT", "CanonicalActionName", "CollectionOf", "Consumes", "Contact",
+			"ContentType", "Credentials", "DELETE", "Default", "DefaultMedia",
+			"Description", "Docs", "Email", "Enum", "Example", "Expose", "Files",
+			"Format", "Function", "GET", "HEAD", "HashOf", "Header", "Headers",
+			"Host", "ImplicitFlow", "JWTSecurity", "License", "Link", "Links",
+			"MaxAge", "MaxLength", "Maximum", "Media", "MediaType", "Member",
+			"Metadata", "Methods", "MinLength", "Minimum", "MultipartForm", "Name",
+			"NoExample", "NoSecurity", "OAuth2Security", "OPTIONS",
+			"OptionalPayload", "Origin", "PATCH", "POST", "PUT", "Package", "Param",
+			"Params", "Parent", "PasswordFlow", "Pattern", "Payload", "Produces",
+			"Reference", "Required", "Resource", "Response", "ResponseTemplate",
+			"Routing", "Scheme", "Scope", "Security", "Status", "TRACE",
+			"TermsOfService", "Title", "TokenURL", "Trait", "Type", "TypeName",
+			"URL", "UseTrait", "Version", "View",
+		},
+		"goa.design/goa/v3/dsl": {
+			"API", "APIKey", "APIKeyField", "APIKeySecurity", "AccessToken",
+			"AccessTokenField", "Any", "ArrayOf", "Attribute", "Attributes",
+			"AuthorizationCodeFlow", "BasicAuthSecurity", "Body", "Boolean", "Bytes",
+			"CONNECT", "ClientCredentialsFlow", "CollectionOf", "Contact",
+			"ContentType", "ConvertTo", "Cookie", "CreateFrom", "DELETE", "Default",
+			"Description", "Docs", "Elem", "Email", "Empty", "Enum", "Error",
+			"Example", "ExclusiveMaximum", "ExclusiveMinimum", "Extend", "Fault",
+			"Field", "Files", "Float32", "Float64", "Format", "FormatCIDR",
+			"FormatDate", "FormatDateTime", "FormatEmail", "FormatHostname",
+			"FormatIP", "FormatIPv4", "FormatIPv6", "FormatJSON", "FormatMAC",
+			"FormatRFC1123", "FormatRegexp", "FormatURI", "FormatUUID", "GET",
+			"GRPC", "HEAD", "HTTP", "Header", "Headers", "Host", "ImplicitFlow",
+			"Int", "Int32", "Int64", "JWTSecurity", "Key", "License", "MapOf",
+			"MapParams", "MaxLength", "Maximum", "Message", "Meta", "Metadata",
+			"Method", "MinLength", "Minimum", "MultipartRequest", "Name",
+			"NoSecurity", "OAuth2Security", "OPTIONS", "PATCH", "POST", "PUT",
+			"Param", "Params", "Password", "PasswordField", "PasswordFlow", "Path",
+			"Pattern", "Payload", "Reference", "Required", "Response", "Result",
+			"ResultType", "Scope", "Security", "Server", "Service", "Services",
+			"StreamingPayload", "StreamingResult", "String", "TRACE", "Tag",
+			"Temporary", "TermsOfService", "Timeout", "Title", "Token", "TokenField",
+			"Type", "TypeName", "UInt", "UInt32", "UInt64", "URI", "URL", "Username",
+			"UsernameField", "Value", "Variable", "Version", "View",
+		},
+	}
+	// The names of the standard responses are constants of the design
+	// package of goa v1 and, prefixed with Status, of the dsl package of goa
+	// v3.
+	for _, name := range responseNames {
+		exports["github.com/goadesign/goa/design"] = append(exports["github.com/goadesign/goa/design"], name)
+		exports["goa.design/goa/v3/dsl"] = append(exports["goa.design/goa/v3/dsl"], "Status"+name)
+	}
+	return exports
+}()
+
+// reservedIdentifiers holds the identifiers of dslExports.
+var reservedIdentifiers = func() map[string]bool {
+	reserved := make(map[string]bool)
+	for _, names := range dslExports {
+		for _, name := range names {
+			reserved[name] = true
+		}
+	}
+	return reserved
+}()
+
+// identifier returns the Go identifier of the variable that defines the type
+// or the media type named name. Identifiers exported by the dot-imported DSL
+// packages get a trailing underscore, like goa does for reserved words.
+func identifier(name string) string {
+	id := codegen.Goify(name, true)
+	if reservedIdentifiers[id] {
+		id += "_"
+	}
+	return id
+}
@@ -0,0 +1,127 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestIdentifier(t *testing.T) {
+	cases := map[string]struct {
+		name     string
+		expected string
+	}{
+		"type":     {"pet_store", "PetStore"},
+		"media":    {"ErrorMedia", "ErrorMedia_"},
+		"response": {"NotFound", "NotFound_"},
+	}
+	for k, tc := range cases {
+		if actual := identifier(tc.name); actual != tc.expected {
+			t.Errorf("%s: got %s, expected %s", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestRenderReservedMediaType(t *testing.T) {
+	document := `swagger: "2.0"
+paths:
+  /pets:
+    get:
+      operationId: list
+      responses:
+        "400": {description: Bad request, schema: {$ref: "#/definitions/Error"}}
+definitions:
+  Error:
+    type: object
+    properties:
+      message: {type: string}
+`
+	api, err := Convert(strings.NewReader(document), Options{})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := Render(api, buf, RenderOptions{}); err != nil {
+		t.Fatalf("Render returned %s", err)
+	}
+	actual := buf.String()
+	for _, expected := range []string{"var ErrorMedia_ = MediaType(", "Response(BadRequest, ErrorMedia_, ", `TypeName("ErrorMedia")`} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("%s is not rendered in\n%s", expected, actual)
+		}
+	}
+}
+
+func TestRenderReservedIdentifiers(t *testing.T) {
+	document := `swagger: "2.0"
+info: {title: reserved, version: "1.0"}
+paths:
+  /things:
+    post:
+      operationId: create
+      parameters:
+      - {name: body, in: body, schema: {$ref: "#/definitions/Type"}}
+      responses:
+        "201": {description: Created, schema: {$ref: "#/definitions/API"}}
+        "400": {description: Bad request, schema: {$ref: "#/definitions/Error"}}
+definitions:
+  String: {type: object, properties: {value: {type: string}}}
+  Any: {type: object, properties: {value: {$ref: "#/definitions/String"}}}
+  File: {type: object, properties: {name: {type: string}}}
+  API: {type: object, properties: {files: {type: array, items: {$ref: "#/definitions/File"}}}}
+  Type: {type: object, properties: {any: {$ref: "#/definitions/Any"}}}
+  Error: {type: object, properties: {message: {type: string}}}
+`
+	for _, target := range []string{TargetV1, TargetV3} {
+		api, err := Convert(strings.NewReader(document), Options{Target: target})
+		if err != nil {
+			t.Fatalf("%s: Convert returned %s", target, err)
+		}
+		buf := new(bytes.Buffer)
+		if err := Render(api, buf, RenderOptions{Target: target}); err != nil {
+			t.Fatalf("%s: Render returned %s", target, err)
+		}
+		if err := typeCheckDesign(buf.String()); err != nil {
+			t.Errorf("%s: the design does not compile: %s\n%s", target, err, buf)
+		}
+	}
+}
+
+// typeCheckDesign type-checks source, a rendered design, against packages
+// that export the identifiers of dslExports as variadic functions, which is
+// enough to detect the identifiers that clash with the dot-imported DSL.
+func typeCheckDesign(source string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "design.go", source, 0)
+	if err != nil {
+		return err
+	}
+	conf := types.Config{Importer: dslImporter{}}
+	_, err = conf.Check("design", fset, []*ast.File{file}, nil)
+	return err
+}
+
+// dslImporter imports the packages of dslExports.
+type dslImporter struct{}
+
+func (dslImporter) Import(path string) (*types.Package, error) {
+	names, ok := dslExports[path]
+	if !ok {
+		return nil, fmt.Errorf("unexpected import %q", path)
+	}
+	pkg := types.NewPackage(path, path[strings.LastIndex(path, "/")+1:])
+	any := types.NewInterfaceType(nil, nil)
+	params := types.NewTuple(types.NewVar(token.NoPos, pkg, "args", types.NewSlice(any)))
+	results := types.NewTuple(types.NewVar(token.NoPos, pkg, "", any))
+	signature := types.NewSignatureType(nil, nil, nil, params, results, true)
+	for _, name := range names {
+		pkg.Scope().Insert(types.NewFunc(token.NoPos, pkg, name, signature))
+	}
+	pkg.MarkComplete()
+	return pkg, nil
+}
@@ -0,0 +1,126 @@
+package convert
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stdinSource is the source that reads a swagger document from stdin.
+const stdinSource = "-"
+
+// documentLoader reads swagger documents from local files, stdin or HTTP(S)
+// URLs.
+type documentLoader struct {
+	stdin   io.Reader
+	client  *http.Client
+	headers http.Header
+	// cacheDir is the directory where fetched documents are cached. The
+	// cached copy is used when a document cannot be fetched.
+	cacheDir string
+}
+
+// newDocumentLoader returns a loader whose requests time out after timeout
+// and carry headers, which are formatted as "Name: value".
+func newDocumentLoader(timeout time.Duration, headers []string, cacheDir string) (*documentLoader, error) {
+	h := make(http.Header)
+	for _, header := range headers {
+		i := strings.Index(header, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid header %q", header)
+		}
+		h.Add(strings.TrimSpace(header[:i]), strings.TrimSpace(header[i+1:]))
+	}
+	return &documentLoader{
+		stdin:    os.Stdin,
+		client:   &http.Client{Timeout: timeout},
+		headers:  h,
+		cacheDir: cacheDir,
+	}, nil
+}
+
+// isURL returns true if source is an HTTP(S) URL.
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// sourcePath returns the path of source, which is used to detect the format
+// of the document.
+func sourcePath(source string) string {
+	if isURL(source) {
+		if u, err := url.Parse(source); err == nil {
+			return u.Path
+		}
+	}
+	return source
+}
+
+// load reads the document of source.
+func (l *documentLoader) load(source string) ([]byte, error) {
+	switch {
+	case source == stdinSource:
+		return ioutil.ReadAll(l.stdin)
+	case isURL(source):
+		return l.fetch(source)
+	default:
+		return ioutil.ReadFile(source)
+	}
+}
+
+// fetch downloads the document at rawurl. The document is stored in the cache
+// directory if any, and read from it when the download fails.
+func (l *documentLoader) fetch(rawurl string) ([]byte, error) {
+	data, err := l.download(rawurl)
+	if l.cacheDir == "" {
+		return data, err
+	}
+	cache := filepath.Join(l.cacheDir, cacheKey(rawurl))
+	if err != nil {
+		cached, cacheErr := ioutil.ReadFile(cache)
+		if cacheErr != nil {
+			return nil, err
+		}
+		return cached, nil
+	}
+	if err := os.MkdirAll(l.cacheDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(cache, data, 0644); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// download requests the document at rawurl.
+func (l *documentLoader) download(rawurl string) ([]byte, error) {
+	req, err := http.NewRequest("GET", rawurl, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range l.headers {
+		req.Header[name] = values
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s: unexpected status %s", rawurl, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// cacheKey returns the name of the cached copy of the document at rawurl.
+func cacheKey(rawurl string) string {
+	sum := sha256.Sum256([]byte(rawurl))
+	return hex.EncodeToString(sum[:])
+}
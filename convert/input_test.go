@@ -0,0 +1,123 @@
+package convert
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocumentLoaderLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"swagger":"2.0"}`))
+	}))
+	defer server.Close()
+
+	loader, err := newDocumentLoader(time.Second, []string{"Authorization: Bearer token"}, "")
+	if err != nil {
+		t.Fatalf("newDocumentLoader returned %s", err)
+	}
+	loader.stdin = strings.NewReader(`swagger: "2.0"`)
+
+	file, err := ioutil.TempFile("", "swagger")
+	if err != nil {
+		t.Fatalf("TempFile returned %s", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"swagger":"2.0","info":{}}`)
+	file.Close()
+
+	cases := map[string]struct {
+		source   string
+		expected string
+	}{
+		"file": {
+			source:   file.Name(),
+			expected: `{"swagger":"2.0","info":{}}`,
+		},
+		"stdin": {
+			source:   "-",
+			expected: `swagger: "2.0"`,
+		},
+		"url": {
+			source:   server.URL + "/swagger.json",
+			expected: `{"swagger":"2.0"}`,
+		},
+	}
+	for k, tc := range cases {
+		actual, err := loader.load(tc.source)
+		if err != nil {
+			t.Fatalf("%s: load returned %s", k, err)
+		}
+		if string(actual) != tc.expected {
+			t.Errorf("%s: got %s, expected %s", k, actual, tc.expected)
+		}
+	}
+
+	loader.headers = nil
+	if _, err := loader.load(server.URL); err == nil {
+		t.Errorf("load accepted an unexpected status")
+	}
+}
+
+func TestDocumentLoaderCache(t *testing.T) {
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"swagger":"2.0"}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("TempDir returned %s", err)
+	}
+	defer os.RemoveAll(dir)
+	loader, err := newDocumentLoader(time.Second, nil, dir)
+	if err != nil {
+		t.Fatalf("newDocumentLoader returned %s", err)
+	}
+	if _, err := loader.load(server.URL); err != nil {
+		t.Fatalf("load returned %s", err)
+	}
+	available = false
+	actual, err := loader.load(server.URL)
+	if err != nil {
+		t.Fatalf("load returned %s with the cache", err)
+	}
+	if string(actual) != `{"swagger":"2.0"}` {
+		t.Errorf("got %s from the cache", actual)
+	}
+	if _, err := loader.load(server.URL + "/other"); err == nil {
+		t.Errorf("load accepted an uncached failure")
+	}
+}
+
+func TestNewDocumentLoader(t *testing.T) {
+	if _, err := newDocumentLoader(time.Second, []string{"invalid"}, ""); err == nil {
+		t.Errorf("newDocumentLoader accepted an invalid header")
+	}
+}
+
+func TestSourcePath(t *testing.T) {
+	cases := map[string]string{
+		"swagger.yaml":                           "swagger.yaml",
+		"-":                                      "-",
+		"https://example.com/api.yaml?version=2": "/api.yaml",
+	}
+	for source, expected := range cases {
+		if actual := sourcePath(source); actual != expected {
+			t.Errorf("%s: got %s, expected %s", source, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,804 @@
+package convert
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operationVerbs are the operations that swagger supports in a path item.
+var operationVerbs = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// referencePrefixes maps the prefixes of OpenAPI 3.x references to the
+// prefixes of swagger references.
+var referencePrefixes = map[string]string{
+	"#/components/schemas/":    "#/definitions/",
+	"#/components/parameters/": "#/parameters/",
+	"#/components/responses/":  "#/responses/",
+}
+
+// inlinedPrefixes are the prefixes of the references to the components of
+// OpenAPI 3.x that swagger has no equivalent for. These references are
+// replaced with the components they point to.
+var inlinedPrefixes = []string{
+	"#/components/headers/",
+	"#/components/requestBodies/",
+	"#/components/examples/",
+}
+
+// formMediaTypes are the media types of the request bodies that become form
+// data parameters.
+var formMediaTypes = []string{"application/x-www-form-urlencoded", "multipart/form-data"}
+
+// isOpenAPI3 returns true if document is an OpenAPI 3.x document.
+func isOpenAPI3(document interface{}) bool {
+	m, ok := document.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	version, ok := m["openapi"].(string)
+	return ok && strings.HasPrefix(version, "3.")
+}
+
+// openAPIToSwagger converts an OpenAPI 3.x document to the equivalent swagger
+// document so that both are converted to the same design. It also returns the
+// warnings about the constructs that swagger cannot express, whose pointers
+// refer to document.
+func openAPIToSwagger(document map[string]interface{}) (map[string]interface{}, []Diagnostic, error) {
+	if _, err := inlineComponents(document, document, nil); err != nil {
+		return nil, nil, err
+	}
+	swagger := map[string]interface{}{
+		"swagger": "2.0",
+	}
+	c := &openAPIConverter{document: document}
+	for _, key := range []string{"info", "tags", "externalDocs", "security"} {
+		if v, ok := document[key]; ok {
+			swagger[key] = v
+		}
+	}
+	if servers, ok := document["servers"].([]interface{}); ok {
+		if err := convertServers(swagger, servers); err != nil {
+			return nil, nil, err
+		}
+	}
+	components, _ := document["components"].(map[string]interface{})
+	if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+		definitions := make(map[string]interface{})
+		for _, name := range sortedKeys(schemas) {
+			restore := c.at("components", "schemas", name)
+			definitions[name] = c.convertSchema(schemas[name])
+			restore()
+		}
+		swagger["definitions"] = definitions
+	}
+	if parameters, ok := components["parameters"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{})
+		for _, name := range sortedKeys(parameters) {
+			restore := c.at("components", "parameters", name)
+			if p, ok := c.convertParameter(parameters[name]); ok {
+				converted[name] = p
+			}
+			restore()
+		}
+		swagger["parameters"] = converted
+	}
+	if responses, ok := components["responses"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{})
+		for _, name := range sortedKeys(responses) {
+			restore := c.at("components", "responses", name)
+			converted[name], _ = c.convertResponse(responses[name])
+			restore()
+		}
+		swagger["responses"] = converted
+	}
+	dropped := make(map[string]bool)
+	if schemes, ok := components["securitySchemes"].(map[string]interface{}); ok {
+		definitions := make(map[string]interface{})
+		for _, name := range sortedKeys(schemes) {
+			restore := c.at("components", "securitySchemes", name)
+			definition := c.convertSecurityScheme(schemes[name])
+			restore()
+			if definition == nil {
+				dropped[name] = true
+				continue
+			}
+			definitions[name] = definition
+		}
+		swagger["securityDefinitions"] = definitions
+	}
+	paths := make(map[string]interface{})
+	if p, ok := document["paths"].(map[string]interface{}); ok {
+		for _, key := range sortedKeys(p) {
+			m, ok := p[key].(map[string]interface{})
+			if !ok || strings.HasPrefix(key, "x-") {
+				paths[key] = p[key]
+				continue
+			}
+			restore := c.at("paths", key)
+			paths[key] = c.convertPathItem(m)
+			restore()
+		}
+	}
+	swagger["paths"] = paths
+	diagnostics := append(c.diagnostics, removeRequirements(swagger, dropped)...)
+	return rewriteReferences(swagger).(map[string]interface{}), diagnostics, nil
+}
+
+// openAPIConverter converts the nodes of an OpenAPI 3.x document to swagger.
+type openAPIConverter struct {
+	// document is the OpenAPI 3.x document being converted.
+	document map[string]interface{}
+	// pointer holds the reference tokens of the node of document being
+	// converted.
+	pointer []string
+	// diagnostics holds the warnings about the constructs that swagger cannot
+	// express.
+	diagnostics []Diagnostic
+}
+
+// at moves to the child node at tokens of the node being converted. It
+// returns a function that moves back.
+func (c *openAPIConverter) at(tokens ...string) func() {
+	n := len(c.pointer)
+	c.pointer = append(c.pointer, tokens...)
+	return func() { c.pointer = c.pointer[:n] }
+}
+
+// warnf records a warning about the node being converted.
+func (c *openAPIConverter) warnf(format string, args ...interface{}) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Severity: SeverityWarning,
+		Pointer:  jsonPointer(c.pointer),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// inlineComponents replaces the references in node to the components that
+// inlinedPrefixes lists with the components of document they point to.
+// inlining holds the references being inlined to detect cycles.
+func inlineComponents(node interface{}, document map[string]interface{}, inlining []string) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok && isInlinedReference(ref) {
+			for i, r := range inlining {
+				if r == ref {
+					return nil, fmt.Errorf("circular reference: %s", strings.Join(append(inlining[i:], ref), " -> "))
+				}
+			}
+			target, err := lookupPointer(document, ref)
+			if err != nil {
+				return nil, err
+			}
+			return inlineComponents(target, document, append(inlining, ref))
+		}
+		for k, v := range n {
+			inlined, err := inlineComponents(v, document, inlining)
+			if err != nil {
+				return nil, err
+			}
+			n[k] = inlined
+		}
+	case []interface{}:
+		for i, v := range n {
+			inlined, err := inlineComponents(v, document, inlining)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = inlined
+		}
+	}
+	return node, nil
+}
+
+// isInlinedReference returns true if ref is a reference to a component that
+// is inlined.
+func isInlinedReference(ref string) bool {
+	for _, prefix := range inlinedPrefixes {
+		if strings.HasPrefix(ref, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeRequirements removes the security requirements of swagger and of its
+// operations that refer to dropped, the security schemes that are not
+// converted, and returns the warnings about them. An operation whose
+// requirements are all removed inherits the requirements of swagger.
+func removeRequirements(swagger map[string]interface{}, dropped map[string]bool) []Diagnostic {
+	var diagnostics []Diagnostic
+	remove := func(node map[string]interface{}, tokens ...string) {
+		requirements, ok := node["security"].([]interface{})
+		if !ok || len(requirements) == 0 {
+			return
+		}
+		var kept []interface{}
+		for i, r := range requirements {
+			requirement, _ := r.(map[string]interface{})
+			name, ok := droppedScheme(requirement, dropped)
+			if !ok {
+				kept = append(kept, r)
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				Pointer:  jsonPointer(append(tokens, "security", strconv.Itoa(i))),
+				Message:  fmt.Sprintf("security requirement refers to the security scheme %q that is not converted and is removed", name),
+			})
+		}
+		if len(kept) == 0 {
+			delete(node, "security")
+			return
+		}
+		node["security"] = kept
+	}
+	remove(swagger)
+	paths, _ := swagger["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, verb := range operationVerbs {
+			if operation, ok := item[verb].(map[string]interface{}); ok {
+				remove(operation, "paths", path, verb)
+			}
+		}
+	}
+	return diagnostics
+}
+
+// droppedScheme returns the first of the schemes that requirement refers to
+// that is in dropped.
+func droppedScheme(requirement map[string]interface{}, dropped map[string]bool) (string, bool) {
+	for _, name := range sortedKeys(requirement) {
+		if dropped[name] {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// sortedKeys returns the sorted keys of m.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// convertServers sets host, basePath and schemes of swagger from the first
+// server. Only the schemes of the servers with the same host are collected.
+func convertServers(swagger map[string]interface{}, servers []interface{}) error {
+	var host string
+	var schemes []interface{}
+	for i, s := range servers {
+		server, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		raw, _ := server["url"].(string)
+		u, err := url.Parse(expandServerVariables(raw, server))
+		if err != nil {
+			return fmt.Errorf("servers.%d: %s", i, err)
+		}
+		if i == 0 {
+			host = u.Host
+			if u.Host != "" {
+				swagger["host"] = u.Host
+			}
+			if path := strings.TrimSuffix(u.Path, "/"); path != "" {
+				swagger["basePath"] = path
+			}
+		}
+		if u.Scheme != "" && u.Host == host {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	if len(schemes) > 0 {
+		swagger["schemes"] = schemes
+	}
+	return nil
+}
+
+// expandServerVariables replaces the variables in the URL of server with their
+// default values.
+func expandServerVariables(raw string, server map[string]interface{}) string {
+	variables, _ := server["variables"].(map[string]interface{})
+	for name, v := range variables {
+		variable, _ := v.(map[string]interface{})
+		if def, ok := variable["default"].(string); ok {
+			raw = strings.Replace(raw, "{"+name+"}", def, -1)
+		}
+	}
+	return raw
+}
+
+// convertPathItem converts a path item of OpenAPI 3.x.
+func (c *openAPIConverter) convertPathItem(item map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+	for key, v := range item {
+		if strings.HasPrefix(key, "x-") || key == "$ref" {
+			converted[key] = v
+		}
+	}
+	if parameters, ok := item["parameters"].([]interface{}); ok {
+		restore := c.at("parameters")
+		converted["parameters"] = c.convertParameters(parameters)
+		restore()
+	}
+	for _, verb := range operationVerbs {
+		operation, ok := item[verb].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		restore := c.at(verb)
+		converted[verb] = c.convertOperation(operation)
+		restore()
+	}
+	return converted
+}
+
+// convertOperation converts an operation of OpenAPI 3.x. The request body
+// becomes a body parameter.
+func (c *openAPIConverter) convertOperation(operation map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+	for key, v := range operation {
+		switch key {
+		case "parameters", "requestBody", "responses", "callbacks", "servers":
+		default:
+			converted[key] = v
+		}
+	}
+	var parameters []interface{}
+	if p, ok := operation["parameters"].([]interface{}); ok {
+		restore := c.at("parameters")
+		parameters = c.convertParameters(p)
+		restore()
+	}
+	if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+		restore := c.at("requestBody")
+		bodyParameters, consumes := c.convertRequestBody(body)
+		restore()
+		parameters = append(parameters, bodyParameters...)
+		if len(consumes) > 0 {
+			converted["consumes"] = consumes
+		}
+	}
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+	if responses, ok := operation["responses"].(map[string]interface{}); ok {
+		var produces []string
+		convertedResponses := make(map[string]interface{})
+		for _, code := range sortedKeys(responses) {
+			restore := c.at("responses", code)
+			r, mediaTypes := c.convertResponse(responses[code])
+			restore()
+			convertedResponses[code] = r
+			for _, mediaType := range mediaTypes {
+				if !containsString(produces, mediaType) {
+					produces = append(produces, mediaType)
+				}
+			}
+		}
+		converted["responses"] = convertedResponses
+		if len(produces) > 0 {
+			sort.Strings(produces)
+			converted["produces"] = produces
+		}
+	}
+	return converted
+}
+
+// convertParameters converts parameters of OpenAPI 3.x. Cookie parameters are
+// dropped with a warning because swagger does not support them.
+func (c *openAPIConverter) convertParameters(parameters []interface{}) []interface{} {
+	var converted []interface{}
+	for i, parameter := range parameters {
+		restore := c.at(strconv.Itoa(i))
+		if p, ok := c.convertParameter(parameter); ok {
+			converted = append(converted, p)
+		}
+		restore()
+	}
+	return converted
+}
+
+// convertParameter converts a parameter of OpenAPI 3.x. The keywords of its
+// schema are moved to the parameter itself. It returns false for the cookie
+// parameters, which swagger does not support.
+func (c *openAPIConverter) convertParameter(parameter interface{}) (interface{}, bool) {
+	p, ok := parameter.(map[string]interface{})
+	if !ok {
+		return parameter, true
+	}
+	if _, ok := p["$ref"]; ok {
+		return p, true
+	}
+	if p["in"] == "cookie" {
+		c.warnf("cookie parameter %q is not supported by swagger and is ignored", p["name"])
+		return nil, false
+	}
+	converted := make(map[string]interface{})
+	for key, v := range p {
+		switch key {
+		case "schema", "style", "explode", "examples", "content", "deprecated", "allowReserved":
+		default:
+			converted[key] = v
+		}
+	}
+	schema, ok := p["schema"].(map[string]interface{})
+	if !ok {
+		return converted, true
+	}
+	if _, ok := schema["$ref"]; ok {
+		converted["schema"] = schema
+		return converted, true
+	}
+	restore := c.at("schema")
+	convertedSchema := c.convertSchema(schema).(map[string]interface{})
+	restore()
+	for key, v := range convertedSchema {
+		switch key {
+		case "description", "example":
+			if _, ok := converted[key]; !ok {
+				converted[key] = v
+			}
+		default:
+			converted[key] = v
+		}
+	}
+	if p["explode"] == false {
+		converted["collectionFormat"] = "csv"
+	} else if schema["type"] == "array" && (p["in"] == "query" || p["in"] == "formData") {
+		converted["collectionFormat"] = "multi"
+	}
+	return converted, true
+}
+
+// convertRequestBody converts a request body of OpenAPI 3.x to a body
+// parameter, or to form data parameters if it has no JSON media type and is
+// a form. It also returns the media types of the request body.
+func (c *openAPIConverter) convertRequestBody(body map[string]interface{}) ([]interface{}, []string) {
+	content, _ := body["content"].(map[string]interface{})
+	if mediaTypes, selected := selectMediaType(content); containsString(formMediaTypes, selected) {
+		mediaType, _ := content[selected].(map[string]interface{})
+		defer c.at("content", selected, "schema")()
+		return c.formParameters(mediaType["schema"]), mediaTypes
+	}
+	parameter := map[string]interface{}{
+		"name": "body",
+		"in":   "body",
+	}
+	for _, key := range []string{"description", "required"} {
+		if v, ok := body[key]; ok {
+			parameter[key] = v
+		}
+	}
+	for key, v := range body {
+		if strings.HasPrefix(key, "x-") {
+			parameter[key] = v
+		}
+	}
+	restore := c.at("content")
+	mediaTypes, schema := c.contentSchema(content)
+	restore()
+	if schema != nil {
+		parameter["schema"] = schema
+	}
+	return []interface{}{parameter}, mediaTypes
+}
+
+// formParameters converts the schema of a form request body to form data
+// parameters, one per property. Binary properties become files and the
+// object properties, which form data parameters cannot express, are dropped
+// with a warning.
+func (c *openAPIConverter) formParameters(schema interface{}) []interface{} {
+	s, ok := c.resolveSchema(schema).(map[string]interface{})
+	properties, _ := s["properties"].(map[string]interface{})
+	if !ok || len(properties) == 0 {
+		c.warnf("form request body has no properties and is ignored")
+		return nil
+	}
+	required, _ := s["required"].([]interface{})
+	var parameters []interface{}
+	for _, name := range sortedKeys(properties) {
+		restore := c.at("properties", name)
+		property, _ := c.convertSchema(c.resolveSchema(properties[name])).(map[string]interface{})
+		if property["type"] == "object" || property["properties"] != nil {
+			c.warnf("form field %q is an object, which form data parameters cannot express, and is ignored", name)
+			restore()
+			continue
+		}
+		restore()
+		parameter := map[string]interface{}{
+			"name": name,
+			"in":   "formData",
+		}
+		for key, v := range property {
+			parameter[key] = v
+		}
+		for _, r := range required {
+			if r == name {
+				parameter["required"] = true
+			}
+		}
+		if parameter["type"] == "string" && parameter["format"] == "binary" {
+			parameter["type"] = "file"
+			delete(parameter, "format")
+		}
+		if parameter["type"] == "array" {
+			parameter["collectionFormat"] = "multi"
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// resolveSchema returns the schema of the document that schema refers to, or
+// schema itself if it is not a reference.
+func (c *openAPIConverter) resolveSchema(schema interface{}) interface{} {
+	s, _ := schema.(map[string]interface{})
+	ref, ok := s["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	resolved, err := lookupPointer(c.document, ref)
+	if err != nil {
+		c.warnf("%s", err)
+		return nil
+	}
+	return resolved
+}
+
+// convertResponse converts a response of OpenAPI 3.x. It also returns the
+// media types of the response.
+func (c *openAPIConverter) convertResponse(response interface{}) (interface{}, []string) {
+	r, ok := response.(map[string]interface{})
+	if !ok {
+		return response, nil
+	}
+	if _, ok := r["$ref"]; ok {
+		return r, nil
+	}
+	converted := make(map[string]interface{})
+	for key, v := range r {
+		switch key {
+		case "content", "headers", "links":
+		default:
+			converted[key] = v
+		}
+	}
+	if headers, ok := r["headers"].(map[string]interface{}); ok {
+		convertedHeaders := make(map[string]interface{})
+		for _, name := range sortedKeys(headers) {
+			restore := c.at("headers", name)
+			h, _ := c.convertParameter(headers[name])
+			restore()
+			if m, ok := h.(map[string]interface{}); ok {
+				delete(m, "required")
+				delete(m, "schema")
+			}
+			convertedHeaders[name] = h
+		}
+		converted["headers"] = convertedHeaders
+	}
+	content, _ := r["content"].(map[string]interface{})
+	restore := c.at("content")
+	mediaTypes, schema := c.contentSchema(content)
+	restore()
+	if schema != nil {
+		converted["schema"] = schema
+	}
+	return converted, mediaTypes
+}
+
+// contentSchema returns the sorted media types of content and the schema of
+// the media type that selectMediaType selects.
+func (c *openAPIConverter) contentSchema(content map[string]interface{}) ([]string, interface{}) {
+	mediaTypes, selected := selectMediaType(content)
+	if len(mediaTypes) == 0 {
+		return nil, nil
+	}
+	mediaType, _ := content[selected].(map[string]interface{})
+	schema, ok := mediaType["schema"]
+	if !ok {
+		return mediaTypes, nil
+	}
+	defer c.at(selected, "schema")()
+	return mediaTypes, c.convertSchema(schema)
+}
+
+// selectMediaType returns the sorted media types of content and its JSON
+// media type, or its first media type if it has no JSON one.
+func selectMediaType(content map[string]interface{}) ([]string, string) {
+	mediaTypes := sortedKeys(content)
+	if len(mediaTypes) == 0 {
+		return nil, ""
+	}
+	for _, mediaType := range mediaTypes {
+		if strings.Contains(mediaType, "json") {
+			return mediaTypes, mediaType
+		}
+	}
+	return mediaTypes, mediaTypes[0]
+}
+
+// convertSecurityScheme converts a security scheme of OpenAPI 3.x. It returns
+// nil for the schemes that swagger cannot express, which are dropped, and
+// warns if the scheme is dropped or only partially converted.
+func (c *openAPIConverter) convertSecurityScheme(scheme interface{}) map[string]interface{} {
+	s, ok := scheme.(map[string]interface{})
+	if !ok {
+		c.warnf("security scheme is not an object and is ignored")
+		return nil
+	}
+	definition := make(map[string]interface{})
+	if description, ok := s["description"]; ok {
+		definition["description"] = description
+	}
+	switch s["type"] {
+	case "http":
+		switch strings.ToLower(fmt.Sprint(s["scheme"])) {
+		case "basic":
+			definition["type"] = "basic"
+		case "bearer":
+			definition["type"] = "apiKey"
+			definition["in"] = "header"
+			definition["name"] = "Authorization"
+			if format, ok := s["bearerFormat"]; ok {
+				c.warnf("http security scheme \"bearer\" is converted to an apiKey security scheme in the Authorization header, the bearer semantics and the %v format are lost", format)
+			} else {
+				c.warnf("http security scheme \"bearer\" is converted to an apiKey security scheme in the Authorization header, the bearer semantics are lost")
+			}
+		default:
+			c.warnf("http security scheme %q is not supported and is ignored", s["scheme"])
+			return nil
+		}
+	case "apiKey":
+		if s["in"] == "cookie" {
+			c.warnf("apiKey security scheme in cookie is not supported and is ignored")
+			return nil
+		}
+		definition["type"] = "apiKey"
+		definition["in"] = s["in"]
+		definition["name"] = s["name"]
+	case "oauth2":
+		flows, _ := s["flows"].(map[string]interface{})
+		var converted string
+		var ignored []string
+		for _, f := range []struct{ openapi, swagger string }{
+			{"authorizationCode", "accessCode"},
+			{"implicit", "implicit"},
+			{"password", "password"},
+			{"clientCredentials", "application"},
+		} {
+			flow, ok := flows[f.openapi].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if converted != "" {
+				ignored = append(ignored, f.openapi)
+				continue
+			}
+			converted = f.openapi
+			definition["type"] = "oauth2"
+			definition["flow"] = f.swagger
+			for _, key := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+				if v, ok := flow[key]; ok {
+					definition[key] = v
+				}
+			}
+		}
+		if converted == "" {
+			c.warnf("oauth2 security scheme has no supported flow and is ignored")
+			return nil
+		}
+		if len(ignored) > 0 {
+			c.warnf("only the %s flow is converted, swagger supports one flow per oauth2 security scheme and %s is ignored", converted, strings.Join(ignored, " and "))
+		}
+	default:
+		c.warnf("security scheme of type %q is not supported and is ignored", s["type"])
+		return nil
+	}
+	return definition
+}
+
+// convertSchema converts a schema of OpenAPI 3.x. Type arrays of OpenAPI 3.1
+// are reduced to their first non-null type and the examples become example.
+// The oneOf and anyOf compositions are dropped with a warning.
+func (c *openAPIConverter) convertSchema(schema interface{}) interface{} {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(s))
+		for _, key := range sortedKeys(s) {
+			v := s[key]
+			switch key {
+			case "nullable", "writeOnly", "deprecated", "discriminator", "xml":
+			case "oneOf", "anyOf":
+				c.warnf("composition with %s is not supported by swagger and is ignored", key)
+			case "default", "enum", "example", "examples":
+				converted[key] = v
+			case "properties":
+				properties, ok := v.(map[string]interface{})
+				if !ok {
+					break
+				}
+				convertedProperties := make(map[string]interface{}, len(properties))
+				for _, name := range sortedKeys(properties) {
+					restore := c.at(key, name)
+					convertedProperties[name] = c.convertSchema(properties[name])
+					restore()
+				}
+				converted[key] = convertedProperties
+			default:
+				restore := c.at(key)
+				converted[key] = c.convertSchema(v)
+				restore()
+			}
+		}
+		if types, ok := s["type"].([]interface{}); ok {
+			delete(converted, "type")
+			for _, t := range types {
+				if t != "null" {
+					converted["type"] = t
+					break
+				}
+			}
+		}
+		if examples, ok := s["examples"].([]interface{}); ok {
+			delete(converted, "examples")
+			if _, ok := converted["example"]; !ok && len(examples) > 0 {
+				converted["example"] = examples[0]
+			}
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(s))
+		for i, v := range s {
+			restore := c.at(strconv.Itoa(i))
+			converted[i] = c.convertSchema(v)
+			restore()
+		}
+		return converted
+	default:
+		return schema
+	}
+}
+
+// rewriteReferences rewrites the references of OpenAPI 3.x in node to the
+// corresponding references of swagger.
+func rewriteReferences(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, v := range n {
+			if ref, ok := v.(string); ok && key == "$ref" {
+				for prefix, replacement := range referencePrefixes {
+					if strings.HasPrefix(ref, prefix) {
+						n[key] = replacement + strings.TrimPrefix(ref, prefix)
+					}
+				}
+				continue
+			}
+			n[key] = rewriteReferences(v)
+		}
+	case []interface{}:
+		for i, v := range n {
+			n[i] = rewriteReferences(v)
+		}
+	}
+	return node
+}
+
+// containsString returns true if s contains v.
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
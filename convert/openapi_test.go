@@ -0,0 +1,161 @@
+package convert
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIToSwagger(t *testing.T) {
+	cases := map[string]struct {
+		document string
+		expected string
+	}{
+		"with servers": {
+			document: `{"openapi":"3.0.0","servers":[{"url":"https://{env}.example.com/v1/","variables":{"env":{"default":"api"}}},{"url":"http://api.example.com/v1"},{"url":"https://staging.example.com/v1"}]}`,
+			expected: `{"swagger":"2.0","host":"api.example.com","basePath":"/v1","schemes":["https","http"],"paths":{}}`,
+		},
+		"with components": {
+			document: `{"openapi":"3.1.0","components":{"schemas":{"Pet":{"type":"object","properties":{"id":{"type":["integer","null"]},"tag":{"type":"string","nullable":true,"examples":["dog"]}}}},"parameters":{"limit":{"name":"limit","in":"query","schema":{"type":"integer","maximum":100}},"session":{"name":"session","in":"cookie","schema":{"type":"string"}}},"securitySchemes":{"basic":{"type":"http","scheme":"basic"},"oauth":{"type":"oauth2","flows":{"clientCredentials":{"tokenUrl":"https://example.com/token","scopes":{"read":"Read"}}}}}}}`,
+			expected: `{"swagger":"2.0","paths":{},"definitions":{"Pet":{"type":"object","properties":{"id":{"type":"integer"},"tag":{"type":"string","example":"dog"}}}},"parameters":{"limit":{"name":"limit","in":"query","type":"integer","maximum":100}},"securityDefinitions":{"basic":{"type":"basic"},"oauth":{"type":"oauth2","flow":"application","tokenUrl":"https://example.com/token","scopes":{"read":"Read"}}}}`,
+		},
+		"with inlined components": {
+			document: `{"openapi":"3.0.0","paths":{"/pets":{"post":{"requestBody":{"$ref":"#/components/requestBodies/Pet"},"responses":{"201":{"description":"Created","headers":{"Location":{"$ref":"#/components/headers/Location"}}}}}}},"components":{"requestBodies":{"Pet":{"required":true,"content":{"application/json":{"schema":{"type":"object"},"examples":{"cat":{"$ref":"#/components/examples/Cat"}}}}}},"headers":{"Location":{"schema":{"type":"string"}}},"examples":{"Cat":{"value":{"name":"Kitty"}}}}}`,
+			expected: `{"swagger":"2.0","paths":{"/pets":{"post":{"consumes":["application/json"],"parameters":[{"name":"body","in":"body","required":true,"schema":{"type":"object"}}],"responses":{"201":{"description":"Created","headers":{"Location":{"type":"string"}}}}}}}}`,
+		},
+		"with form request body": {
+			document: `{"openapi":"3.0.0","paths":{"/pets/{id}/photo":{"put":{"requestBody":{"content":{"multipart/form-data":{"schema":{"$ref":"#/components/schemas/Photo"}}}},"responses":{"204":{"description":"No Content"}}}}},"components":{"schemas":{"Photo":{"type":"object","required":["file"],"properties":{"file":{"type":"string","format":"binary"},"tags":{"type":"array","items":{"type":"string"}},"caption":{"type":"string","maxLength":100}}}}}}`,
+			expected: `{"swagger":"2.0","paths":{"/pets/{id}/photo":{"put":{"consumes":["multipart/form-data"],"parameters":[{"name":"caption","in":"formData","type":"string","maxLength":100},{"name":"file","in":"formData","required":true,"type":"file"},{"name":"tags","in":"formData","type":"array","items":{"type":"string"},"collectionFormat":"multi"}],"responses":{"204":{"description":"No Content"}}}}},"definitions":{"Photo":{"type":"object","required":["file"],"properties":{"file":{"type":"string","format":"binary"},"tags":{"type":"array","items":{"type":"string"}},"caption":{"type":"string","maxLength":100}}}}}`,
+		},
+		"with operations": {
+			document: `{"openapi":"3.0.0","paths":{"/pets":{"post":{"operationId":"createPet","requestBody":{"required":true,"content":{"application/xml":{"schema":{"type":"string"}},"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}},"responses":{"201":{"description":"Created","headers":{"Location":{"schema":{"type":"string"}}},"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}},"default":{"$ref":"#/components/responses/Error"}}}}}}`,
+			expected: `{"swagger":"2.0","paths":{"/pets":{"post":{"operationId":"createPet","consumes":["application/json","application/xml"],"produces":["application/json"],"parameters":[{"name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/Pet"}}],"responses":{"201":{"description":"Created","headers":{"Location":{"type":"string"}},"schema":{"$ref":"#/definitions/Pet"}},"default":{"$ref":"#/responses/Error"}}}}}}`,
+		},
+	}
+	for k, tc := range cases {
+		var document map[string]interface{}
+		if err := json.Unmarshal([]byte(tc.document), &document); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		if !isOpenAPI3(document) {
+			t.Fatalf("%s: isOpenAPI3 returned false", k)
+		}
+		swagger, _, err := openAPIToSwagger(document)
+		if err != nil {
+			t.Fatalf("%s: openAPIToSwagger returned %s", k, err)
+		}
+		// Round trip through JSON to compare the documents regardless of the
+		// types of their values.
+		data, err := json.Marshal(swagger)
+		if err != nil {
+			t.Fatalf("%s: Marshal returned %s", k, err)
+		}
+		var actual, expected interface{}
+		if err := json.Unmarshal(data, &actual); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		if err := json.Unmarshal([]byte(tc.expected), &expected); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: \ngot:\n%s\nexpected:\n%s", k, data, tc.expected)
+		}
+	}
+}
+
+func TestOpenAPIToSwaggerSecurity(t *testing.T) {
+	document := `{"openapi":"3.0.0","security":[{"session":[]},{"basic":[]}],"paths":{"/pets":{"get":{"security":[{"oidc":[],"basic":[]}],"responses":{"200":{"description":"OK"}}},"post":{"security":[{"oauth":["write"]}],"responses":{"201":{"description":"Created"}}}}},"components":{"securitySchemes":{"basic":{"type":"http","scheme":"basic"},"digest":{"type":"http","scheme":"digest"},"session":{"type":"apiKey","in":"cookie","name":"session"},"oidc":{"type":"openIdConnect","openIdConnectUrl":"https://example.com/.well-known/openid-configuration"},"oauth":{"type":"oauth2","flows":{"implicit":{"authorizationUrl":"https://example.com/authorize","scopes":{"write":"Write"}},"clientCredentials":{"tokenUrl":"https://example.com/token","scopes":{"write":"Write"}}}}}}}`
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		t.Fatalf("Unmarshal returned %s", err)
+	}
+	swagger, diagnostics, err := openAPIToSwagger(decoded)
+	if err != nil {
+		t.Fatalf("openAPIToSwagger returned %s", err)
+	}
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		t.Fatalf("Marshal returned %s", err)
+	}
+	var actual, expected interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("Unmarshal returned %s", err)
+	}
+	expectedDocument := `{"swagger":"2.0","security":[{"basic":[]}],"paths":{"/pets":{"get":{"responses":{"200":{"description":"OK"}}},"post":{"security":[{"oauth":["write"]}],"responses":{"201":{"description":"Created"}}}}},"securityDefinitions":{"basic":{"type":"basic"},"oauth":{"type":"oauth2","flow":"implicit","authorizationUrl":"https://example.com/authorize","scopes":{"write":"Write"}}}}`
+	if err := json.Unmarshal([]byte(expectedDocument), &expected); err != nil {
+		t.Fatalf("Unmarshal returned %s", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", data, expectedDocument)
+	}
+	var warnings []string
+	for _, d := range diagnostics {
+		warnings = append(warnings, d.String())
+	}
+	expectedWarnings := []string{
+		`warning: /components/securitySchemes/digest: http security scheme "digest" is not supported and is ignored`,
+		`warning: /components/securitySchemes/oauth: only the implicit flow is converted, swagger supports one flow per oauth2 security scheme and clientCredentials is ignored`,
+		`warning: /components/securitySchemes/oidc: security scheme of type "openIdConnect" is not supported and is ignored`,
+		`warning: /components/securitySchemes/session: apiKey security scheme in cookie is not supported and is ignored`,
+		`warning: /security/0: security requirement refers to the security scheme "session" that is not converted and is removed`,
+		`warning: /paths/~1pets/get/security/0: security requirement refers to the security scheme "oidc" that is not converted and is removed`,
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expectedWarnings, "\n"))
+	}
+}
+
+func TestOpenAPIToSwaggerWarnings(t *testing.T) {
+	cases := map[string]struct {
+		document string
+		expected []string
+	}{
+		"with cookie parameters": {
+			document: `{"openapi":"3.0.0","paths":{"/pets":{"parameters":[{"name":"limit","in":"query","schema":{"type":"integer"}},{"name":"session","in":"cookie","schema":{"type":"string"}}],"get":{"parameters":[{"name":"tracking","in":"cookie","schema":{"type":"string"}}],"responses":{"200":{"description":"OK"}}}}},"components":{"parameters":{"session":{"name":"session","in":"cookie","schema":{"type":"string"}}}}}`,
+			expected: []string{
+				`warning: /components/parameters/session: cookie parameter "session" is not supported by swagger and is ignored`,
+				`warning: /paths/~1pets/parameters/1: cookie parameter "session" is not supported by swagger and is ignored`,
+				`warning: /paths/~1pets/get/parameters/0: cookie parameter "tracking" is not supported by swagger and is ignored`,
+			},
+		},
+		"with bearer security schemes": {
+			document: `{"openapi":"3.0.0","components":{"securitySchemes":{"jwt":{"type":"http","scheme":"bearer","bearerFormat":"JWT"},"token":{"type":"http","scheme":"bearer"}}}}`,
+			expected: []string{
+				`warning: /components/securitySchemes/jwt: http security scheme "bearer" is converted to an apiKey security scheme in the Authorization header, the bearer semantics and the JWT format are lost`,
+				`warning: /components/securitySchemes/token: http security scheme "bearer" is converted to an apiKey security scheme in the Authorization header, the bearer semantics are lost`,
+			},
+		},
+		"with form request bodies": {
+			document: `{"openapi":"3.0.0","paths":{"/pets":{"post":{"requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"type":"object","properties":{"name":{"type":"string"},"owner":{"type":"object","properties":{"name":{"type":"string"}}}}}}}},"responses":{"201":{"description":"Created"}}},"put":{"requestBody":{"content":{"multipart/form-data":{}}},"responses":{"204":{"description":"No Content"}}}}}}`,
+			expected: []string{
+				`warning: /paths/~1pets/put/requestBody/content/multipart~1form-data/schema: form request body has no properties and is ignored`,
+				`warning: /paths/~1pets/post/requestBody/content/application~1x-www-form-urlencoded/schema/properties/owner: form field "owner" is an object, which form data parameters cannot express, and is ignored`,
+			},
+		},
+		"with compositions": {
+			document: `{"openapi":"3.0.0","paths":{"/pets":{"get":{"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"type":"array","items":{"anyOf":[{"$ref":"#/components/schemas/Cat"},{"$ref":"#/components/schemas/Dog"}]}}}}}}}}},"components":{"schemas":{"Cat":{"type":"object"},"Dog":{"type":"object"},"Pet":{"type":"object","properties":{"owner":{"oneOf":[{"type":"string"},{"type":"integer"}]}}}}}}`,
+			expected: []string{
+				`warning: /components/schemas/Pet/properties/owner: composition with oneOf is not supported by swagger and is ignored`,
+				`warning: /paths/~1pets/get/responses/200/content/application~1json/schema/items: composition with anyOf is not supported by swagger and is ignored`,
+			},
+		},
+	}
+	for k, tc := range cases {
+		var document map[string]interface{}
+		if err := json.Unmarshal([]byte(tc.document), &document); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		_, diagnostics, err := openAPIToSwagger(document)
+		if err != nil {
+			t.Fatalf("%s: openAPIToSwagger returned %s", k, err)
+		}
+		var warnings []string
+		for _, d := range diagnostics {
+			warnings = append(warnings, d.String())
+		}
+		if !reflect.DeepEqual(warnings, tc.expected) {
+			t.Errorf("%s: \ngot:\n%s\nexpected:\n%s", k, strings.Join(warnings, "\n"), strings.Join(tc.expected, "\n"))
+		}
+	}
+}
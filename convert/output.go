@@ -0,0 +1,97 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// designFile is a file of a design package.
+type designFile struct {
+	name     string
+	template string
+	data     interface{}
+}
+
+// designFiles returns the files of the design package of api. The API and its
+// security schemes go to api.go, each resource goes to its own file and the
+// types and the media types go to types.go and media_types.go.
+func designFiles(api *design.APIDefinition) []designFile {
+	files := []designFile{
+		{"api.go", "apiFile", api},
+	}
+	var names []string
+	for name := range api.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		files = append(files, designFile{
+			name:     resourceFileName(name),
+			template: "resourceFile",
+			data: &design.APIDefinition{
+				Resources: map[string]*design.ResourceDefinition{
+					name: api.Resources[name],
+				},
+			},
+		})
+	}
+	if len(api.Types) > 0 {
+		files = append(files, designFile{"types.go", "typeFile", api})
+	}
+	if len(api.MediaTypes) > 0 {
+		files = append(files, designFile{"media_types.go", "mediaTypeFile", api})
+	}
+	return files
+}
+
+// resourceFileName returns the name of the file of the resource named name.
+// The suffix prevents the name from being read as a build constraint (e.g.
+// linux.go) or colliding with the other files.
+func resourceFileName(name string) string {
+	return codegen.SnakeCase(codegen.Goify(name, true)) + "_resource.go"
+}
+
+// renderTemplate executes the template named name of t with data and formats
+// the result. If the result cannot be formatted, the error points at the
+// template and at the swagger node found in origins that produced the code.
+func renderTemplate(t *template.Template, name string, data interface{}, origins map[interface{}]string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := t.ExecuteTemplate(buf, name, data); err != nil {
+		return nil, err
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, syntaxError(t, name, data, origins, buf.Bytes(), err)
+	}
+	return formatted, nil
+}
+
+// writeDesignPackage renders the files of the design package of api into dir.
+func writeDesignPackage(dir string, t *template.Template, api *design.APIDefinition, origins map[interface{}]string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, f := range designFiles(api) {
+		source, err := renderTemplate(t, f.template, f.data, origins)
+		if err != nil {
+			if e, ok := err.(*SyntaxError); ok {
+				e.Filename = f.name
+				return e
+			}
+			return fmt.Errorf("%s: %s", f.name, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, f.name), source, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
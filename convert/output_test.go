@@ -0,0 +1,54 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+)
+
+func TestDesignFiles(t *testing.T) {
+	cases := map[string]struct {
+		api      *design.APIDefinition
+		expected []string
+	}{
+		"with definitions": {
+			api: &design.APIDefinition{
+				Resources: map[string]*design.ResourceDefinition{
+					"pets":     &design.ResourceDefinition{Name: "pets"},
+					"petOwner": &design.ResourceDefinition{Name: "petOwner"},
+					"linux":    &design.ResourceDefinition{Name: "linux"},
+				},
+				Types: map[string]*design.UserTypeDefinition{
+					"Pet": &design.UserTypeDefinition{TypeName: "Pet"},
+				},
+				MediaTypes: map[string]*design.MediaTypeDefinition{
+					"application/vnd.pet+json": &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+							TypeName:            "PetMedia",
+						},
+						Identifier: "application/vnd.pet+json",
+					},
+				},
+			},
+			expected: []string{"api.go", "linux_resource.go", "pet_owner_resource.go", "pets_resource.go", "types.go", "media_types.go"},
+		},
+		"without definitions": {
+			api:      &design.APIDefinition{},
+			expected: []string{"api.go"},
+		},
+	}
+	for k, tc := range cases {
+		var actual []string
+		for _, f := range designFiles(tc.api) {
+			actual = append(actual, f.name)
+			if _, err := renderTemplate(tmpl, f.template, f.data, nil); err != nil {
+				t.Errorf("%s: %s: renderTemplate returned %s", k, f.name, err)
+			}
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
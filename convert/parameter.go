@@ -0,0 +1,270 @@
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+// parametersToParams converts the top-level parameters of swagger to the
+// params of the API, which goa shares across all the actions. Only path and
+// query parameters can be shared this way.
+func (c *converter) parametersToParams() (*design.AttributeDefinition, error) {
+	var parameters []*genswagger.Parameter
+	for _, p := range c.parameters {
+		if p.In == "path" || p.In == "query" {
+			parameters = append(parameters, p)
+		}
+	}
+	return c.parametersToAttribute(parameters)
+}
+
+// operationParameters sets the params, headers and payload of action from
+// the parameters of operation and the parameters shared by its path.
+// Parameters that are defined at the top level of swagger are already
+// included in the API params and are skipped.
+func (c *converter) operationParameters(action *design.ActionDefinition, pathParameters []*genswagger.Parameter, operation *genswagger.Operation) error {
+	var params, queryParams, headers, formData []*genswagger.Parameter
+	var body *genswagger.Parameter
+	for _, p := range mergeParameters(pathParameters, operation.Parameters) {
+		if c.isSharedParameter(p) {
+			continue
+		}
+		switch p.In {
+		case "path":
+			params = append(params, p)
+		case "query":
+			params = append(params, p)
+			queryParams = append(queryParams, p)
+		case "header":
+			headers = append(headers, p)
+		case "body":
+			body = p
+		case "formData":
+			formData = append(formData, p)
+		default:
+			restore := c.moveTo(c.parameterPointers[p]...)
+			c.warnf("parameter %q in %q is not supported and is ignored", p.Name, p.In)
+			restore()
+		}
+	}
+	var err error
+	if action.Params, err = c.parametersToAttribute(params); err != nil {
+		return err
+	}
+	if action.QueryParams, err = c.parametersToAttribute(queryParams); err != nil {
+		return err
+	}
+	if action.Headers, err = c.parametersToAttribute(headers); err != nil {
+		return err
+	}
+	switch {
+	case body != nil:
+		restore := c.moveTo(append(c.parameterPointers[body], "schema")...)
+		payload, err := c.schemaToPayload(body.Schema, action.Name)
+		if err != nil {
+			return err
+		}
+		restore()
+		action.Payload = payload
+		action.PayloadOptional = !body.Required
+	case len(formData) > 0:
+		attribute, err := c.parametersToAttribute(formData)
+		if err != nil {
+			return err
+		}
+		action.Payload = c.newPayloadType(attribute, action.Name)
+		action.PayloadOptional = true
+		for _, p := range formData {
+			if p.Required {
+				action.PayloadOptional = false
+			}
+		}
+		action.PayloadMultipart = c.isMultipart(operation, formData)
+	}
+	return nil
+}
+
+// isMultipart returns true if the form data parameters of operation are
+// multipart encoded, that is if operation consumes multipart/form-data or if
+// one of the parameters is a file, which goa only accepts in multipart
+// payloads.
+func (c *converter) isMultipart(operation *genswagger.Operation, formData []*genswagger.Parameter) bool {
+	for _, p := range formData {
+		if p.Type == "file" {
+			return true
+		}
+	}
+	consumes := operation.Consumes
+	if consumes == nil {
+		consumes = c.consumes
+	}
+	for _, mimeType := range consumes {
+		if mimeType == "multipart/form-data" {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeParameters returns the parameters of a path overridden by the
+// parameters of one of its operations. Parameters are identified by their
+// name and location.
+func mergeParameters(pathParameters, operationParameters []*genswagger.Parameter) []*genswagger.Parameter {
+	var merged []*genswagger.Parameter
+	for _, p := range pathParameters {
+		overridden := false
+		for _, o := range operationParameters {
+			if o.Name == p.Name && o.In == p.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, p)
+		}
+	}
+	return append(merged, operationParameters...)
+}
+
+// isSharedParameter returns true if p is one of the top-level parameters
+// that become API params.
+func (c *converter) isSharedParameter(p *genswagger.Parameter) bool {
+	if p.In != "path" && p.In != "query" {
+		return false
+	}
+	for _, shared := range c.parameters {
+		if reflect.DeepEqual(p, shared) {
+			return true
+		}
+	}
+	return false
+}
+
+// parametersToAttribute converts parameters to an object attribute whose
+// required validation lists the required parameters. Path parameters are
+// always required by goa so they are not listed. It returns nil if there are
+// no parameters.
+func (c *converter) parametersToAttribute(parameters []*genswagger.Parameter) (*design.AttributeDefinition, error) {
+	if len(parameters) == 0 {
+		return nil, nil
+	}
+	object := make(design.Object)
+	var required []string
+	for _, p := range parameters {
+		restore := c.moveTo(c.parameterPointers[p]...)
+		switch p.CollectionFormat {
+		case "", "csv", "multi":
+		default:
+			c.warnf("collection format %q is not supported, goa uses csv", p.CollectionFormat)
+		}
+		schema := p.Schema
+		if schema == nil {
+			schema = parameterToSchema(p)
+		}
+		attribute, err := c.schemaToAttribute(schema)
+		if err != nil {
+			return nil, err
+		}
+		restore()
+		if attribute.Description == "" {
+			attribute.Description = p.Description
+		}
+		object[p.Name] = attribute
+		if p.Required && p.In != "path" {
+			required = append(required, p.Name)
+		}
+	}
+	attribute := &design.AttributeDefinition{
+		Type: object,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		attribute.Validation = &dslengine.ValidationDefinition{
+			Required: required,
+		}
+	}
+	return attribute, nil
+}
+
+// schemaToPayload returns the payload described by schema. References to
+// object definitions use the corresponding user types, inline objects get a
+// new user type named after the action, or after their x-goa-type-name vendor
+// extension, and other schemas are used as is.
+func (c *converter) schemaToPayload(schema *genschema.JSONSchema, actionName string) (*design.UserTypeDefinition, error) {
+	attribute, err := c.schemaToAttribute(schema)
+	if err != nil {
+		return nil, err
+	}
+	switch t := attribute.Type.(type) {
+	case *design.UserTypeDefinition:
+		return t, nil
+	case design.Object:
+		if name, ok := c.stringExtension(extensionTypeName); ok {
+			return c.newType(attribute, name), nil
+		}
+		return c.newPayloadType(attribute, actionName), nil
+	}
+	return &design.UserTypeDefinition{
+		AttributeDefinition: attribute,
+	}, nil
+}
+
+// newPayloadType registers a user type for the inline payload of the action
+// named actionName.
+func (c *converter) newPayloadType(attribute *design.AttributeDefinition, actionName string) *design.UserTypeDefinition {
+	return c.newType(attribute, codegen.Goify(actionName, true)+c.naming.PayloadSuffix)
+}
+
+// newType registers a user type named base. The type is suffixed with a
+// number if the name is taken.
+func (c *converter) newType(attribute *design.AttributeDefinition, base string) *design.UserTypeDefinition {
+	name := base
+	for i := 2; c.isTypeName(name); i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	t := &design.UserTypeDefinition{
+		AttributeDefinition: attribute,
+		TypeName:            name,
+	}
+	c.types[name] = t
+	c.record(t)
+	return t
+}
+
+// isTypeName returns true if name is the name of a type.
+func (c *converter) isTypeName(name string) bool {
+	if _, ok := c.types[name]; ok {
+		return true
+	}
+	for _, t := range c.types {
+		if t.TypeName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parameterToSchema returns the schema that describes a non-body parameter.
+func parameterToSchema(p *genswagger.Parameter) *genschema.JSONSchema {
+	return &genschema.JSONSchema{
+		Type:        genschema.JSONType(p.Type),
+		Description: p.Description,
+		Items:       itemsToSchema(p.Items),
+		Enum:        p.Enum,
+		Format:      p.Format,
+		Pattern:     p.Pattern,
+		Minimum:     p.Minimum,
+		Maximum:     p.Maximum,
+		MinLength:   p.MinLength,
+		MaxLength:   p.MaxLength,
+		MinItems:    p.MinItems,
+		MaxItems:    p.MaxItems,
+	}
+}
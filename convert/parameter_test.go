@@ -0,0 +1,110 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+func TestOperationParameters(t *testing.T) {
+	limit := &genswagger.Parameter{Name: "limit", In: "query", Type: "integer"}
+	c := newConverter(genswagger.Swagger{
+		Parameters: map[string]*genswagger.Parameter{
+			"limit": limit,
+		},
+	})
+	action := &design.ActionDefinition{Name: "update"}
+	pathParameters := []*genswagger.Parameter{
+		{Name: "id", In: "path", Required: true, Type: "integer"},
+		{Name: "tag", In: "query", Type: "integer"},
+	}
+	operation := &genswagger.Operation{
+		Parameters: []*genswagger.Parameter{
+			{Name: "limit", In: "query", Type: "integer"},
+			{Name: "tag", In: "query", Required: true, Type: "string"},
+			{Name: "X-Token", In: "header", Type: "string"},
+			{Name: "body", In: "body", Schema: &genschema.JSONSchema{
+				Type: genschema.Object,
+				Properties: map[string]*genschema.JSONSchema{
+					"name": &genschema.JSONSchema{Type: genschema.String},
+				},
+			}},
+		},
+	}
+	if err := c.operationParameters(action, pathParameters, operation); err != nil {
+		t.Fatalf("operationParameters returned %s", err)
+	}
+	params := action.Params.Type.ToObject()
+	if len(params) != 2 || params["id"].Type != design.Integer || params["tag"].Type != design.String {
+		t.Errorf("unexpected params %v", params)
+	}
+	if !reflect.DeepEqual(action.Params.Validation.Required, []string{"tag"}) {
+		t.Errorf("unexpected required params %v", action.Params.Validation.Required)
+	}
+	if query := action.QueryParams.Type.ToObject(); len(query) != 1 || query["tag"] == nil {
+		t.Errorf("unexpected query params %v", query)
+	}
+	if headers := action.Headers.Type.ToObject(); len(headers) != 1 || headers["X-Token"] == nil {
+		t.Errorf("unexpected headers %v", headers)
+	}
+	if action.Payload == nil || action.Payload.TypeName != "UpdatePayload" || c.types["UpdatePayload"] != action.Payload {
+		t.Errorf("unexpected payload %v", action.Payload)
+	}
+	if !action.PayloadOptional {
+		t.Errorf("payload is not optional")
+	}
+}
+
+func TestOperationFormData(t *testing.T) {
+	cases := map[string]struct {
+		consumes   []string
+		parameters []*genswagger.Parameter
+		optional   bool
+		multipart  bool
+	}{
+		"urlencoded": {
+			parameters: []*genswagger.Parameter{
+				{Name: "name", In: "formData", Type: "string"},
+				{Name: "age", In: "formData", Type: "integer"},
+			},
+			optional: true,
+		},
+		"required": {
+			parameters: []*genswagger.Parameter{
+				{Name: "name", In: "formData", Required: true, Type: "string"},
+				{Name: "age", In: "formData", Type: "integer"},
+			},
+		},
+		"multipart": {
+			consumes: []string{"multipart/form-data"},
+			parameters: []*genswagger.Parameter{
+				{Name: "name", In: "formData", Type: "string"},
+			},
+			optional:  true,
+			multipart: true,
+		},
+		"file": {
+			parameters: []*genswagger.Parameter{
+				{Name: "photo", In: "formData", Required: true, Type: "file"},
+			},
+			multipart: true,
+		},
+	}
+	for k, tc := range cases {
+		c := newConverter(genswagger.Swagger{})
+		action := &design.ActionDefinition{Name: "upload"}
+		operation := &genswagger.Operation{Consumes: tc.consumes, Parameters: tc.parameters}
+		if err := c.operationParameters(action, nil, operation); err != nil {
+			t.Fatalf("%s: operationParameters returned %s", k, err)
+		}
+		if action.Payload == nil || len(action.Payload.Type.ToObject()) != len(tc.parameters) {
+			t.Errorf("%s: unexpected payload %v", k, action.Payload)
+		}
+		if action.PayloadOptional != tc.optional || action.PayloadMultipart != tc.multipart {
+			t.Errorf("%s: got optional %t and multipart %t, expected %t and %t", k, action.PayloadOptional, action.PayloadMultipart, tc.optional, tc.multipart)
+		}
+	}
+}
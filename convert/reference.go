@@ -0,0 +1,119 @@
+package convert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// definitionsPrefix is the prefix of references to definitions. References to
+// definitions are left in place by the resolver because they are converted to
+// user types.
+const definitionsPrefix = "#/definitions/"
+
+// resolver resolves local JSON references in a swagger document decoded into
+// generic values.
+type resolver struct {
+	document interface{}
+	// resolving holds the references being followed to detect cycles.
+	resolving []string
+}
+
+// resolveReferences replaces every local reference in document with the value
+// it points to, except references to definitions.
+func resolveReferences(document interface{}) (interface{}, error) {
+	r := &resolver{document: document}
+	return r.resolve(document)
+}
+
+func (r *resolver) resolve(node interface{}) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok {
+			if _, ok := definitionName(ref); ok {
+				return n, nil
+			}
+			return r.follow(ref)
+		}
+		for k, v := range n {
+			resolved, err := r.resolve(v)
+			if err != nil {
+				return nil, err
+			}
+			n[k] = resolved
+		}
+	case []interface{}:
+		for i, v := range n {
+			resolved, err := r.resolve(v)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = resolved
+		}
+	}
+	return node, nil
+}
+
+func (r *resolver) follow(ref string) (interface{}, error) {
+	for i, resolving := range r.resolving {
+		if resolving == ref {
+			return nil, fmt.Errorf("circular reference: %s", strings.Join(append(r.resolving[i:], ref), " -> "))
+		}
+	}
+	target, err := lookupPointer(r.document, ref)
+	if err != nil {
+		return nil, err
+	}
+	r.resolving = append(r.resolving, ref)
+	defer func() { r.resolving = r.resolving[:len(r.resolving)-1] }()
+	return r.resolve(target)
+}
+
+// lookupPointer returns the value in document that ref points to. Only local
+// references (e.g. #/parameters/limit) are supported.
+func lookupPointer(document interface{}, ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported reference %q: only local references are supported", ref)
+	}
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return document, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid reference %q", ref)
+	}
+	node := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("unresolved reference %q", ref)
+			}
+			node = v
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("unresolved reference %q", ref)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("unresolved reference %q", ref)
+		}
+	}
+	return node, nil
+}
+
+// definitionName returns the name of the definition that ref points to. It
+// returns false if ref does not point to a definition itself.
+func definitionName(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, definitionsPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(ref, definitionsPrefix)
+	if name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return strings.Replace(strings.Replace(name, "~1", "/", -1), "~0", "~", -1), true
+}
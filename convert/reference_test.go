@@ -0,0 +1,63 @@
+package convert
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResolveReferences(t *testing.T) {
+	cases := map[string]struct {
+		document string
+		expected string
+		err      string
+	}{
+		"with parameter reference": {
+			document: `{"parameters":{"limit":{"name":"limit","in":"query"}},"paths":{"/pets":{"get":{"parameters":[{"$ref":"#/parameters/limit"}]}}}}`,
+			expected: `{"parameters":{"limit":{"name":"limit","in":"query"}},"paths":{"/pets":{"get":{"parameters":[{"name":"limit","in":"query"}]}}}}`,
+		},
+		"with response reference": {
+			document: `{"responses":{"NotFound":{"description":"Not found"}},"paths":{"/pets":{"get":{"responses":{"404":{"$ref":"#/responses/NotFound"}}}}}}`,
+			expected: `{"responses":{"NotFound":{"description":"Not found"}},"paths":{"/pets":{"get":{"responses":{"404":{"description":"Not found"}}}}}}`,
+		},
+		"with definition reference": {
+			document: `{"definitions":{"Pet":{"type":"object"}},"paths":{"/pets":{"get":{"responses":{"200":{"schema":{"$ref":"#/definitions/Pet"}}}}}}}`,
+			expected: `{"definitions":{"Pet":{"type":"object"}},"paths":{"/pets":{"get":{"responses":{"200":{"schema":{"$ref":"#/definitions/Pet"}}}}}}}`,
+		},
+		"with escaped reference": {
+			document: `{"definitions":{"Pet":{"properties":{"a/b":{"type":"string"}}}},"x":{"$ref":"#/definitions/Pet/properties/a~1b"}}`,
+			expected: `{"definitions":{"Pet":{"properties":{"a/b":{"type":"string"}}}},"x":{"type":"string"}}`,
+		},
+		"with circular reference": {
+			document: `{"parameters":{"a":{"$ref":"#/parameters/a"}}}`,
+			err:      "circular reference: #/parameters/a -> #/parameters/a",
+		},
+		"with unresolved reference": {
+			document: `{"paths":{"/pets":{"get":{"parameters":[{"$ref":"#/parameters/limit"}]}}}}`,
+			err:      `unresolved reference "#/parameters/limit"`,
+		},
+	}
+	for k, tc := range cases {
+		var document interface{}
+		if err := json.Unmarshal([]byte(tc.document), &document); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		actual, err := resolveReferences(document)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: got error %v, expected %v", k, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: resolveReferences returned %s", k, err)
+		}
+		var expected interface{}
+		if err := json.Unmarshal([]byte(tc.expected), &expected); err != nil {
+			t.Fatalf("%s: Unmarshal returned %s", k, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: got %v, expected %v", k, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package convert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+// responseNames maps status codes to the names of the responses that goa
+// defines by default.
+var responseNames = map[int]string{
+	100: "Continue",
+	101: "SwitchingProtocols",
+	200: "OK",
+	201: "Created",
+	202: "Accepted",
+	203: "NonAuthoritativeInfo",
+	204: "NoContent",
+	205: "ResetContent",
+	206: "PartialContent",
+	300: "MultipleChoices",
+	301: "MovedPermanently",
+	302: "Found",
+	303: "SeeOther",
+	304: "NotModified",
+	305: "UseProxy",
+	307: "TemporaryRedirect",
+	400: "BadRequest",
+	401: "Unauthorized",
+	402: "PaymentRequired",
+	403: "Forbidden",
+	404: "NotFound",
+	405: "MethodNotAllowed",
+	406: "NotAcceptable",
+	407: "ProxyAuthRequired",
+	408: "RequestTimeout",
+	409: "Conflict",
+	410: "Gone",
+	411: "LengthRequired",
+	412: "PreconditionFailed",
+	413: "RequestEntityTooLarge",
+	414: "RequestURITooLong",
+	415: "UnsupportedMediaType",
+	416: "RequestedRangeNotSatisfiable",
+	417: "ExpectationFailed",
+	418: "Teapot",
+	422: "UnprocessableEntity",
+	500: "InternalServerError",
+	501: "NotImplemented",
+	502: "BadGateway",
+	503: "ServiceUnavailable",
+	504: "GatewayTimeout",
+	505: "HTTPVersionNotSupported",
+}
+
+// operationResponses converts the responses of operation to the responses of
+// action. The default response is skipped because goa has no equivalent.
+func (c *converter) operationResponses(action *design.ActionDefinition, operation *genswagger.Operation) (map[string]*design.ResponseDefinition, error) {
+	if len(operation.Responses) == 0 {
+		return nil, nil
+	}
+	contentType := ""
+	if len(operation.Produces) > 0 {
+		contentType = operation.Produces[0]
+	} else if len(c.produces) > 0 {
+		contentType = c.produces[0]
+	}
+	responses := make(map[string]*design.ResponseDefinition)
+	for code, r := range operation.Responses {
+		back := c.at("responses", code)
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			c.warnf("response %q is not supported and is ignored", code)
+			back()
+			continue
+		}
+		response := &design.ResponseDefinition{
+			Status:      status,
+			Description: r.Description,
+			Parent:      action,
+		}
+		c.record(response)
+		if name, ok := responseNames[status]; ok {
+			response.Name = name
+			response.Standard = true
+		} else {
+			response.Name = fmt.Sprintf("Status%d", status)
+		}
+		if r.Schema != nil {
+			back := c.at("schema")
+			mediaType, err := c.schemaToMediaType(r.Schema, action.Name+response.Name, contentType)
+			if err != nil {
+				return nil, err
+			}
+			if mediaType != nil {
+				response.Type = mediaType
+				response.MediaType = mediaType.Identifier
+			} else {
+				c.warnf("the body of the response is not an object nor an array of objects and is ignored, goa responses require a media type")
+			}
+			back()
+		}
+		if view := c.extension(extensionView); view != nil {
+			c.responseView(response, view)
+		}
+		if len(r.Headers) > 0 {
+			headers := make(design.Object)
+			for name, header := range r.Headers {
+				back := c.at("headers", name)
+				attribute, err := c.schemaToAttribute(headerToSchema(header))
+				if err != nil {
+					return nil, err
+				}
+				back()
+				headers[name] = attribute
+			}
+			response.Headers = &design.AttributeDefinition{
+				Type: headers,
+			}
+		}
+		responses[response.Name] = response
+		back()
+	}
+	if c.target == TargetV3 {
+		success := successResponse(responses)
+		for _, r := range responses {
+			if r.Status >= 200 && r.Status < 300 && r != success {
+				restore := c.at("responses", strconv.Itoa(r.Status))
+				c.warnf("goa v3 methods have one result, the response %d is not converted for goa v3 and is ignored", r.Status)
+				restore()
+			}
+		}
+	}
+	return responses, nil
+}
+
+// schemaToMediaType returns the media type of a response whose body is
+// described by schema. Responses that refer to the same definition share the
+// media type, other responses get their own media type named after name, or
+// after the x-goa-type-name vendor extension of schema. Arrays of objects get
+// the collection of the media type of their items. It returns nil if the body
+// is not an object nor an array of objects.
+func (c *converter) schemaToMediaType(schema *genschema.JSONSchema, name, contentType string) (*design.MediaTypeDefinition, error) {
+	if items, restore := c.collectionItems(schema); items != nil {
+		defer restore()
+		back := c.at("items")
+		defer back()
+		elem, err := c.schemaToMediaType(items, name, contentType)
+		if err != nil || elem == nil {
+			return nil, err
+		}
+		return c.collectionOf(elem), nil
+	}
+	if definition, ok := definitionName(schema.Ref); ok {
+		name = c.typeName(definition)
+	} else if typeName, ok := c.stringExtension(extensionTypeName); ok {
+		name = typeName
+	}
+	identifier := "application/vnd." + strings.ToLower(codegen.Goify(name, false)) + "+json"
+	if mediaType, ok := c.mediaTypes[design.CanonicalIdentifier(identifier)]; ok {
+		return mediaType, nil
+	}
+	attribute, err := c.schemaToAttribute(schema)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := attribute.Type.(*design.UserTypeDefinition); ok {
+		attribute = t.AttributeDefinition
+	}
+	object, ok := attribute.Type.(design.Object)
+	if !ok {
+		return nil, nil
+	}
+	view := make(design.Object)
+	for n, a := range object {
+		view[n] = viewAttribute(a)
+	}
+	mediaType := &design.MediaTypeDefinition{
+		UserTypeDefinition: &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:        object,
+				Description: attribute.Description,
+				Validation:  attribute.Validation,
+				Metadata:    attribute.Metadata,
+				Example:     attribute.Example,
+			},
+			TypeName: codegen.Goify(name, true) + c.naming.MediaTypeSuffix,
+		},
+		Identifier:  identifier,
+		ContentType: contentType,
+	}
+	mediaType.Views = map[string]*design.ViewDefinition{
+		"default": &design.ViewDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: view,
+			},
+			Name:   "default",
+			Parent: mediaType,
+		},
+	}
+	c.mediaTypes[design.CanonicalIdentifier(identifier)] = mediaType
+	c.record(mediaType)
+	return mediaType, nil
+}
+
+// collectionItems returns the items of schema if it describes an array,
+// inline or by reference to a definition. The returned func moves the
+// converter back from the array definition.
+func (c *converter) collectionItems(schema *genschema.JSONSchema) (*genschema.JSONSchema, func()) {
+	if name, ok := definitionName(schema.Ref); ok {
+		if definition, ok := c.definitions[name]; ok && definition.Type == genschema.Array && definition.Items != nil {
+			return definition.Items, c.moveTo("definitions", name)
+		}
+		return nil, nil
+	}
+	if schema.Type == genschema.Array && schema.Items != nil {
+		return schema.Items, func() {}
+	}
+	return nil, nil
+}
+
+// responseView sets the view of response from v, the value of its x-goa-view
+// vendor extension. v is the name of the view or an object with the name and
+// the attributes of the view. The view is added to the media type of the
+// response if it is not defined, with all the attributes if v does not list
+// them.
+func (c *converter) responseView(response *design.ResponseDefinition, v interface{}) {
+	defer c.at(extensionView)()
+	mediaType, ok := response.Type.(*design.MediaTypeDefinition)
+	if !ok {
+		c.warnf("%s is ignored because the response has no media type", extensionView)
+		return
+	}
+	if elem, ok := collectionElem(mediaType); ok {
+		mediaType = elem
+	}
+	var name string
+	var attributes []interface{}
+	switch v := v.(type) {
+	case string:
+		name = v
+	case map[string]interface{}:
+		name, _ = v["name"].(string)
+		attributes, _ = v["attributes"].([]interface{})
+	}
+	if name == "" {
+		c.warnf("%s must be the name of a view or an object with a name and attributes and is ignored", extensionView)
+		return
+	}
+	response.ViewName = name
+	if _, ok := mediaType.Views[name]; ok {
+		return
+	}
+	object, _ := mediaType.Type.(design.Object)
+	view := make(design.Object)
+	for i, a := range attributes {
+		n, _ := a.(string)
+		if _, ok := object[n]; !ok {
+			restore := c.at("attributes", strconv.Itoa(i))
+			c.warnf("attribute %q of view %q is not defined by the media type and is ignored", fmt.Sprint(a), name)
+			restore()
+			continue
+		}
+		view[n] = viewAttribute(object[n])
+	}
+	if attributes == nil {
+		for n, a := range object {
+			view[n] = viewAttribute(a)
+		}
+	}
+	mediaType.Views[name] = &design.ViewDefinition{
+		AttributeDefinition: &design.AttributeDefinition{
+			Type: view,
+		},
+		Name:   name,
+		Parent: mediaType,
+	}
+}
+
+// viewAttribute returns the attribute of a view that lists attribute of its
+// media type. The type, the validation and the metadata are copied from
+// attribute like goa does when it runs the View DSL.
+func viewAttribute(attribute *design.AttributeDefinition) *design.AttributeDefinition {
+	return &design.AttributeDefinition{
+		Type:       attribute.Type,
+		Validation: attribute.Validation,
+		Metadata:   attribute.Metadata,
+	}
+}
+
+// headerToSchema returns the schema that describes header.
+func headerToSchema(header *genswagger.Header) *genschema.JSONSchema {
+	return &genschema.JSONSchema{
+		Type:        genschema.JSONType(header.Type),
+		Description: header.Description,
+		Items:       itemsToSchema(header.Items),
+		Enum:        header.Enum,
+		Format:      header.Format,
+		Pattern:     header.Pattern,
+		Minimum:     header.Minimum,
+		Maximum:     header.Maximum,
+		MinLength:   header.MinLength,
+		MaxLength:   header.MaxLength,
+		MinItems:    header.MinItems,
+		MaxItems:    header.MaxItems,
+	}
+}
@@ -0,0 +1,86 @@
+package convert
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestConvertResponses(t *testing.T) {
+	document := `swagger: "2.0"
+paths:
+  /pets:
+    get:
+      operationId: list
+      responses:
+        "200": {description: OK, schema: {type: array, items: {type: string}}}
+        "206": {description: Partial, schema: {type: array, items: {type: object, properties: {name: {type: string}}}}}
+    post:
+      operationId: create
+      responses:
+        "201": {description: Created, schema: {type: integer}}
+        "202": {description: Accepted, schema: {type: object, additionalProperties: {type: string}}}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	list := api.Resources["pets"].Actions["list"]
+	if list.Responses["OK"].Type != nil || list.Responses["PartialContent"].Type == nil {
+		t.Errorf("got types %v and %v, expected no type and a collection", list.Responses["OK"].Type, list.Responses["PartialContent"].Type)
+	}
+	expected := []string{
+		`warning: /paths/~1pets/get/responses/200/schema: the body of the response is not an object nor an array of objects and is ignored, goa responses require a media type`,
+		`warning: /paths/~1pets/post/responses/201/schema: the body of the response is not an object nor an array of objects and is ignored, goa responses require a media type`,
+		`warning: /paths/~1pets/post/responses/202/schema: the body of the response is not an object nor an array of objects and is ignored, goa responses require a media type`,
+	}
+	sort.Strings(warnings)
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestConvertV3Responses(t *testing.T) {
+	document := `swagger: "2.0"
+paths:
+  /pets:
+    put:
+      operationId: upsert
+      responses:
+        "200": {description: OK}
+        "201": {description: Created}
+        "204": {description: No content}
+        "404": {description: Not found}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Target: TargetV3,
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	var errors []string
+	for _, r := range errorResponses(api.Resources["pets"].Actions["upsert"].Responses) {
+		errors = append(errors, r.Name)
+	}
+	if !reflect.DeepEqual(errors, []string{"NotFound"}) {
+		t.Errorf("got errors %v, expected NotFound", errors)
+	}
+	expected := []string{
+		`warning: /paths/~1pets/put/responses/201: goa v3 methods have one result, the response 201 is not converted for goa v3 and is ignored`,
+		`warning: /paths/~1pets/put/responses/204: goa v3 methods have one result, the response 204 is not converted for goa v3 and is ignored`,
+	}
+	sort.Strings(warnings)
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expected, "\n"))
+	}
+}
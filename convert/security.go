@@ -0,0 +1,116 @@
+package convert
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+// securityDefinitionsToSchemes converts the security definitions of swagger
+// to the security schemes of the converter sorted by name.
+func (c *converter) securityDefinitionsToSchemes(definitions map[string]*genswagger.SecurityDefinition) error {
+	var names []string
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		restore := c.moveTo("securityDefinitions", name)
+		d := definitions[name]
+		scheme := &design.SecuritySchemeDefinition{
+			SchemeName:  name,
+			Type:        d.Type,
+			Description: d.Description,
+		}
+		switch d.Type {
+		case "basic":
+			scheme.Kind = design.BasicAuthSecurityKind
+		case "apiKey":
+			if d.In != "header" && d.In != "query" {
+				return c.errorf("unsupported location %q", d.In)
+			}
+			scheme.Kind = design.APIKeySecurityKind
+			scheme.In = d.In
+			scheme.Name = d.Name
+		case "oauth2":
+			switch d.Flow {
+			case "accessCode", "implicit", "password", "application":
+			default:
+				return c.errorf("unsupported flow %q", d.Flow)
+			}
+			scheme.Kind = design.OAuth2SecurityKind
+			scheme.Flow = d.Flow
+			scheme.AuthorizationURL = d.AuthorizationURL
+			scheme.TokenURL = d.TokenURL
+			scheme.Scopes = d.Scopes
+		default:
+			return c.errorf("unsupported type %q", d.Type)
+		}
+		c.record(scheme)
+		restore()
+		c.securitySchemes = append(c.securitySchemes, scheme)
+	}
+	return nil
+}
+
+// credentialAttributes holds by kind of security scheme the DSL that defines
+// the attributes of the credentials in the payloads of goa v3.
+var credentialAttributes = map[design.SecuritySchemeKind]string{
+	design.BasicAuthSecurityKind: "Username and Password",
+	design.APIKeySecurityKind:    "APIKey",
+	design.OAuth2SecurityKind:    "AccessToken",
+}
+
+// requirementsToSecurity converts security requirements to the security of
+// an API or an action. goa accepts a single scheme so only the first scheme of
+// the first requirement is used. An empty list of requirements, or a single
+// empty requirement, disables the security and nil requirements inherit it.
+// Empty requirements among other alternatives, which make the security
+// optional, are ignored. goa v3 also expects the credentials in the payloads
+// of the secured methods, which the converter cannot infer and leaves to be
+// added. field is the field of the node being converted that holds the
+// requirements.
+func (c *converter) requirementsToSecurity(requirements []map[string][]string, field string) (*design.SecurityDefinition, error) {
+	defer c.at(field)()
+	if requirements == nil {
+		return nil, nil
+	}
+	if len(requirements) == 0 || len(requirements) == 1 && len(requirements[0]) == 0 {
+		return &design.SecurityDefinition{
+			Scheme: &design.SecuritySchemeDefinition{
+				Kind: design.NoSecurityKind,
+			},
+		}, nil
+	}
+	var alternatives []map[string][]string
+	for _, r := range requirements {
+		if len(r) > 0 {
+			alternatives = append(alternatives, r)
+		}
+	}
+	if len(alternatives) < len(requirements) {
+		c.warnf("the empty security requirement makes the security optional, which goa does not support, and is ignored")
+		requirements = alternatives
+	}
+	var names []string
+	for name := range requirements[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(requirements) > 1 || len(names) > 1 {
+		c.warnf("only the security scheme %q is used, goa supports a single scheme", names[0])
+	}
+	for _, scheme := range c.securitySchemes {
+		if scheme.SchemeName == names[0] {
+			if c.target == TargetV3 {
+				c.warnf("the payloads do not define the credentials of security scheme %q, goa v3 requires %s", names[0], credentialAttributes[scheme.Kind])
+			}
+			return &design.SecurityDefinition{
+				Scheme: scheme,
+				Scopes: requirements[0][names[0]],
+			}, nil
+		}
+	}
+	return nil, c.errorf("undefined security scheme %q", names[0])
+}
@@ -0,0 +1,263 @@
+package convert
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+func TestSecurityDefinitionsToSchemes(t *testing.T) {
+	c := newConverter(genswagger.Swagger{})
+	err := c.securityDefinitionsToSchemes(map[string]*genswagger.SecurityDefinition{
+		"oauth": &genswagger.SecurityDefinition{
+			Type:     "oauth2",
+			Flow:     "application",
+			TokenURL: "https://example.com/token",
+		},
+		"basic": &genswagger.SecurityDefinition{
+			Type: "basic",
+		},
+		"key": &genswagger.SecurityDefinition{
+			Type: "apiKey",
+			In:   "query",
+			Name: "api_key",
+		},
+	})
+	if err != nil {
+		t.Fatalf("securityDefinitionsToSchemes returned %s", err)
+	}
+	schemes := c.securitySchemes
+	expected := []struct {
+		name string
+		kind design.SecuritySchemeKind
+	}{
+		{"basic", design.BasicAuthSecurityKind},
+		{"key", design.APIKeySecurityKind},
+		{"oauth", design.OAuth2SecurityKind},
+	}
+	if len(schemes) != len(expected) {
+		t.Fatalf("got %d schemes, expected %d", len(schemes), len(expected))
+	}
+	for i, e := range expected {
+		if schemes[i].SchemeName != e.name || schemes[i].Kind != e.kind {
+			t.Errorf("scheme %d: got %s (%d), expected %s (%d)", i, schemes[i].SchemeName, schemes[i].Kind, e.name, e.kind)
+		}
+	}
+
+	err = newConverter(genswagger.Swagger{}).securityDefinitionsToSchemes(map[string]*genswagger.SecurityDefinition{
+		"key": &genswagger.SecurityDefinition{
+			Type: "apiKey",
+			In:   "cookie",
+		},
+	})
+	if err == nil {
+		t.Fatalf("securityDefinitionsToSchemes accepted a cookie api key")
+	}
+	if expected := "/securityDefinitions/key: unsupported location \"cookie\""; err.Error() != expected {
+		t.Errorf("got error %q, expected %q", err, expected)
+	}
+}
+
+func TestRequirementsToSecurity(t *testing.T) {
+	oauth := &design.SecuritySchemeDefinition{SchemeName: "oauth"}
+	c := newConverter(genswagger.Swagger{})
+	c.securitySchemes = []*design.SecuritySchemeDefinition{oauth}
+	cases := map[string]struct {
+		requirements []map[string][]string
+		expected     *design.SecurityDefinition
+	}{
+		"inherited": {
+			requirements: nil,
+			expected:     nil,
+		},
+		"no security": {
+			requirements: []map[string][]string{},
+			expected: &design.SecurityDefinition{
+				Scheme: &design.SecuritySchemeDefinition{Kind: design.NoSecurityKind},
+			},
+		},
+		"optional": {
+			requirements: []map[string][]string{{}},
+			expected: &design.SecurityDefinition{
+				Scheme: &design.SecuritySchemeDefinition{Kind: design.NoSecurityKind},
+			},
+		},
+		"with scopes": {
+			requirements: []map[string][]string{{"oauth": {"read"}}},
+			expected: &design.SecurityDefinition{
+				Scheme: oauth,
+				Scopes: []string{"read"},
+			},
+		},
+	}
+	for k, tc := range cases {
+		actual, err := c.requirementsToSecurity(tc.requirements, "security")
+		if err != nil {
+			t.Fatalf("%s: requirementsToSecurity returned %s", k, err)
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: got %#v, expected %#v", k, actual, tc.expected)
+		}
+	}
+
+	if len(c.diagnostics) != 0 {
+		t.Errorf("got diagnostics %v, expected none", c.diagnostics)
+	}
+	if _, err := c.requirementsToSecurity([]map[string][]string{{"oauth": nil}, {"basic": nil}}, "security"); err != nil {
+		t.Fatalf("requirementsToSecurity returned %s", err)
+	}
+	expected := []Diagnostic{{Severity: SeverityWarning, Pointer: "/security", Message: "only the security scheme \"oauth\" is used, goa supports a single scheme"}}
+	if !reflect.DeepEqual(c.diagnostics, expected) {
+		t.Errorf("got diagnostics %v, expected %v", c.diagnostics, expected)
+	}
+	if _, err := c.requirementsToSecurity([]map[string][]string{{"basic": nil}}, "security"); err == nil {
+		t.Errorf("requirementsToSecurity accepted an undefined scheme")
+	}
+}
+
+func TestRequirementsToSecurityOptional(t *testing.T) {
+	key := &design.SecuritySchemeDefinition{SchemeName: "api_key"}
+	c := newConverter(genswagger.Swagger{})
+	c.securitySchemes = []*design.SecuritySchemeDefinition{key}
+	actual, err := c.requirementsToSecurity([]map[string][]string{{}, {"api_key": {}}}, "security")
+	if err != nil {
+		t.Fatalf("requirementsToSecurity returned %s", err)
+	}
+	if actual == nil || actual.Scheme != key {
+		t.Errorf("got %#v, expected the security of api_key", actual)
+	}
+	expected := []Diagnostic{{Severity: SeverityWarning, Pointer: "/security", Message: "the empty security requirement makes the security optional, which goa does not support, and is ignored"}}
+	if !reflect.DeepEqual(c.diagnostics, expected) {
+		t.Errorf("got diagnostics %v, expected %v", c.diagnostics, expected)
+	}
+}
+
+func TestConvertAPINoSecurity(t *testing.T) {
+	document := `swagger: "2.0"
+info: {title: petstore, version: "1.0"}
+securityDefinitions:
+  basic: {type: basic}
+security: []
+paths: {}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.ExecuteTemplate(buf, "api", api); err != nil {
+		t.Fatalf("Execute returned %s", err)
+	}
+	expected := `var _ = API("petstore", func() {
+Title("petstore")
+Version("1.0")
+})`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("\ngot:\n%v\nexpected:\n%v", actual, expected)
+	}
+	expectedWarnings := []string{"warning: /security: empty security requirements are ignored, goa only disables the security of resources and actions"}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("got warnings %v, expected %v", warnings, expectedWarnings)
+	}
+}
+
+func TestRenderV3Security(t *testing.T) {
+	document := `swagger: "2.0"
+info: {title: petstore, version: "1.0"}
+securityDefinitions:
+  api_key: {type: apiKey, in: header, name: X-Key}
+  basic: {type: basic, description: Basic authentication}
+  oauth: {type: oauth2, flow: accessCode, authorizationUrl: "https://auth/authorize", tokenUrl: "https://auth/token", scopes: {read: Read}}
+security:
+- api_key: []
+paths:
+  /pets:
+    get:
+      operationId: list
+      security:
+      - oauth: [read]
+      responses:
+        "204": {description: No Content}
+    delete:
+      operationId: purge
+      security: []
+      responses:
+        "204": {description: No Content}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Target: TargetV3,
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := Render(api, buf, RenderOptions{Target: TargetV3}); err != nil {
+		t.Fatalf("Render returned %s", err)
+	}
+	expected := `package design
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var _ = API("petstore", func() {
+	Title("petstore")
+	Version("1.0")
+	Security("api_key")
+})
+
+var _ = APIKeySecurity("api_key")
+var _ = BasicAuthSecurity("basic", func() {
+	Description("Basic authentication")
+})
+var _ = OAuth2Security("oauth", func() {
+	AuthorizationCodeFlow("https://auth/authorize", "https://auth/token", "")
+	Scope("read", "Read")
+})
+
+var _ = Service("pets", func() {
+	HTTP(func() {
+		Path("/pets")
+	})
+	Method("list", func() {
+		Security("oauth", func() {
+			Scope("read")
+		})
+		HTTP(func() {
+			GET("")
+			Response(StatusNoContent)
+		})
+	})
+	Method("purge", func() {
+		NoSecurity()
+		HTTP(func() {
+			DELETE("")
+			Response(StatusNoContent)
+		})
+	})
+})
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("\ngot:\n%v\nexpected:\n%v", actual, expected)
+	}
+	expectedWarnings := []string{
+		`warning: /security: the payloads do not define the credentials of security scheme "api_key", goa v3 requires APIKey`,
+		`warning: /paths/~1pets/get/security: the payloads do not define the credentials of security scheme "oauth", goa v3 requires AccessToken`,
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expectedWarnings, "\n"))
+	}
+}
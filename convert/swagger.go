@@ -0,0 +1,894 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+// swaggerDocument is a swagger with the fields that genswagger.Swagger does
+// not hold.
+type swaggerDocument struct {
+	genswagger.Swagger
+	Security []map[string][]string `json:"security,omitempty"`
+	// document is the decoded document, which holds the vendor extensions.
+	document interface{}
+	// diagnostics holds the warnings about the constructs of an OpenAPI 3.x
+	// document that are lost in the swagger document.
+	diagnostics []Diagnostic
+}
+
+// loadSwagger decodes data, the document of source, in format, which is
+// detected if empty, into a swagger whose references are resolved except the
+// ones to definitions. External documents are merged and OpenAPI 3.x
+// documents are converted to swagger.
+func (l *documentLoader) loadSwagger(data []byte, source, format string) (swaggerDocument, error) {
+	var swagger swaggerDocument
+	if format == "" {
+		format = detectFormat(sourcePath(source), data)
+	}
+	document, err := decodeDocument(data, format)
+	if err != nil {
+		return swagger, err
+	}
+	document, err = l.bundle(document, source)
+	if err != nil {
+		return swagger, err
+	}
+	var diagnostics []Diagnostic
+	if isOpenAPI3(document) {
+		document, diagnostics, err = openAPIToSwagger(document.(map[string]interface{}))
+		if err != nil {
+			return swagger, err
+		}
+	}
+	document, err = resolveReferences(document)
+	if err != nil {
+		return swagger, err
+	}
+	resolved, err := json.Marshal(boolAdditionalProperties(document, false))
+	if err != nil {
+		return swagger, err
+	}
+	if err := json.Unmarshal(resolved, &swagger); err != nil {
+		return swagger, err
+	}
+	swagger.document = document
+	swagger.diagnostics = diagnostics
+	return swagger, nil
+}
+
+// swaggerToAPI converts swagger to an API. The diagnostics found in swagger
+// are recorded in the converter.
+func (c *converter) swaggerToAPI(swagger swaggerDocument) (*design.APIDefinition, error) {
+	c.document = swagger.document
+	api := design.APIDefinition{
+		Host:     swagger.Host,
+		Schemes:  swagger.Schemes,
+		BasePath: swagger.BasePath,
+	}
+	if len(swagger.Consumes) > 0 {
+		api.Consumes = []*design.EncodingDefinition{
+			&design.EncodingDefinition{
+				MIMETypes: swagger.Consumes,
+				Encoder:   false,
+			},
+		}
+	}
+	if len(swagger.Produces) > 0 {
+		api.Produces = []*design.EncodingDefinition{
+			&design.EncodingDefinition{
+				MIMETypes: swagger.Produces,
+				Encoder:   true,
+			},
+		}
+	}
+	if swagger.Info != nil {
+		api.Name = swagger.Info.Title
+		api.Title = swagger.Info.Title
+		api.Description = swagger.Info.Description
+		api.Version = swagger.Info.Version
+		api.TermsOfService = swagger.Info.TermsOfService
+		api.Contact = swagger.Info.Contact
+		api.License = swagger.Info.License
+	}
+	api.Metadata = c.metadataExtension()
+	api.NoExamples = c.noExamplesExtension()
+	if swagger.ExternalDocs != nil {
+		api.Docs = &design.DocsDefinition{
+			Description: swagger.ExternalDocs.Description,
+			URL:         swagger.ExternalDocs.URL,
+		}
+	}
+	if err := c.securityDefinitionsToSchemes(swagger.SecurityDefinitions); err != nil {
+		return nil, err
+	}
+	api.SecuritySchemes = c.securitySchemes
+	requirements := swagger.Security
+	if requirements == nil && c.defaultSecurity != "" {
+		requirements = []map[string][]string{{c.defaultSecurity: nil}}
+	}
+	security, err := c.requirementsToSecurity(requirements, "security")
+	if err != nil {
+		return nil, err
+	}
+	if security != nil && security.Scheme.Kind == design.NoSecurityKind {
+		back := c.at("security")
+		c.warnf("empty security requirements are ignored, goa only disables the security of resources and actions")
+		back()
+		security = nil
+	}
+	api.Security = security
+	if err := c.definitionsToTypes(); err != nil {
+		return nil, err
+	}
+	api.Types = c.types
+	params, err := c.parametersToParams()
+	if err != nil {
+		return nil, err
+	}
+	api.Params = params
+	resources, err := c.pathsToResources(swagger.Paths)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range swagger.Tags {
+		if resource, ok := resources[tag.Name]; ok && resource.Description == "" {
+			resource.Description = tag.Description
+		}
+	}
+	api.Resources = resources
+	api.MediaTypes = c.mediaTypes
+	return &api, nil
+}
+
+// pathsToResources converts the paths of swagger to resources. Operations are
+// grouped into resources by the strategy of the converter and keyed by their
+// operationId.
+func (c *converter) pathsToResources(paths map[string]interface{}) (map[string]*design.ResourceDefinition, error) {
+	var keys []string
+	for p := range paths {
+		if !strings.HasPrefix(p, "x-") && !matchAny(c.ignoredPaths, p) {
+			keys = append(keys, p)
+		}
+	}
+	sort.Strings(keys)
+	resources := make(map[string]*design.ResourceDefinition)
+	parentPointers := make(map[string][]string)
+	for _, p := range keys {
+		path, err := decodePath(paths[p])
+		if err != nil {
+			return nil, &Error{Pointer: jsonPointer([]string{"paths", p}), Message: err.Error()}
+		}
+		for _, o := range pathOperations(path) {
+			if c.isIgnoredOperation(o.operation) {
+				continue
+			}
+			name := c.operationResource(p, o)
+			resource, ok := resources[name]
+			if !ok {
+				resource = &design.ResourceDefinition{
+					Name:    name,
+					Actions: make(map[string]*design.ActionDefinition),
+				}
+				resources[name] = resource
+			}
+			action, err := c.operationToAction(o.verb, p, path.Parameters, o.operation)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := resource.Actions[action.Name]; ok {
+				return nil, &Error{
+					Pointer: jsonPointer([]string{"paths", p, strings.ToLower(o.verb)}),
+					Message: fmt.Sprintf("duplicate action %q in resource %q", action.Name, name),
+				}
+			}
+			action.Parent = resource
+			resource.Actions[action.Name] = action
+			restore := c.moveTo("paths", p, strings.ToLower(o.verb))
+			if parent, ok := c.stringExtension(extensionParent); ok {
+				switch resource.ParentName {
+				case "":
+					resource.ParentName = parent
+					parentPointers[name] = append(append([]string(nil), c.pointer...), extensionParent)
+				case parent:
+				default:
+					back := c.at(extensionParent)
+					c.warnf("parent %q conflicts with the parent %q of resource %q and is ignored", parent, resource.ParentName, name)
+					back()
+				}
+			}
+			restore()
+		}
+	}
+	c.resolveResources(resources, parentPointers)
+	return resources, nil
+}
+
+// resolveResources sets the base paths, the relative routes and the
+// canonical actions of resources. The base path of a resource that has a
+// parent is relative to the canonical route of the parent, so parents are
+// resolved first. Parents that cannot be used are warned about at
+// parentPointers and removed.
+func (c *converter) resolveResources(resources map[string]*design.ResourceDefinition, parentPointers map[string][]string) {
+	fullPaths := make(map[string]string)
+	var resolve func(resource *design.ResourceDefinition, visiting []string)
+	resolve = func(resource *design.ResourceDefinition, visiting []string) {
+		if _, ok := fullPaths[resource.Name]; ok {
+			return
+		}
+		visiting = append(visiting, resource.Name)
+		var prefix string
+		if resource.ParentName != "" {
+			restore := c.moveTo(parentPointers[resource.Name]...)
+			parent, ok := resources[resource.ParentName]
+			switch {
+			case !ok:
+				c.warnf("parent resource %q is not defined and is ignored", resource.ParentName)
+			case containsString(visiting, parent.Name):
+				c.warnf("parent resource %q refers to resource %q circularly and is ignored", parent.Name, resource.Name)
+			default:
+				resolve(parent, visiting)
+				if canonical, ok := parent.Actions[parent.CanonicalActionName]; ok {
+					prefix = fullPaths[parent.Name] + canonical.Routes[0].Path
+				} else {
+					c.warnf("parent resource %q has no canonical action and is ignored", parent.Name)
+				}
+			}
+			if prefix != "" {
+				if route := outsideRoute(resource, prefix); route != "" {
+					c.warnf("route %q of resource %q is not under the canonical route %q of its parent, the parent is ignored", route, resource.Name, prefix)
+					prefix = ""
+				}
+			}
+			if prefix == "" {
+				resource.ParentName = ""
+			}
+			restore()
+		}
+		resolveResourcePaths(resource, prefix)
+		fullPaths[resource.Name] = prefix + resource.BasePath
+	}
+	var names []string
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		resolve(resources[name], nil)
+	}
+}
+
+// outsideRoute returns the path of a route of resource that is not under
+// prefix, or an empty string if all the routes are under prefix.
+func outsideRoute(resource *design.ResourceDefinition, prefix string) string {
+	var names []string
+	for name := range resource.Actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, route := range resource.Actions[name].Routes {
+			if route.Path != prefix && !strings.HasPrefix(route.Path, prefix+"/") {
+				return route.Path
+			}
+		}
+	}
+	return ""
+}
+
+// operationResource returns the name of the resource of the operation o of
+// the path p.
+func (c *converter) operationResource(p string, o verbOperation) string {
+	switch c.grouping {
+	case GroupByTag:
+		if len(o.operation.Tags) > 0 && o.operation.Tags[0] != "" {
+			return o.operation.Tags[0]
+		}
+	case GroupByExtension:
+		defer c.moveTo("paths", p, strings.ToLower(o.verb))()
+		if name, ok := c.stringExtension(extensionResource); ok {
+			return name
+		}
+	}
+	return resourceName(p)
+}
+
+// resolveResourcePaths sets the base path of resource to the path shared by
+// the routes of its actions up to the first wildcard, and makes the routes
+// relative to it. prefix, the path of the parent of resource, is removed from
+// the routes first. The action routed by GET to the base path followed by a
+// single wildcard (e.g. /pets/:id) becomes the canonical action.
+func resolveResourcePaths(resource *design.ResourceDefinition, prefix string) {
+	var names []string
+	for name := range resource.Actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var base []string
+	first := true
+	for _, name := range names {
+		for _, route := range resource.Actions[name].Routes {
+			route.Path = strings.TrimPrefix(route.Path, prefix)
+			segments := literalSegments(route.Path)
+			if first {
+				base, first = segments, false
+				continue
+			}
+			for i := range base {
+				if i >= len(segments) || segments[i] != base[i] {
+					base = base[:i]
+					break
+				}
+			}
+		}
+	}
+	if len(base) > 0 {
+		resource.BasePath = "/" + strings.Join(base, "/")
+	}
+	for _, name := range names {
+		action := resource.Actions[name]
+		for _, route := range action.Routes {
+			route.Path = strings.TrimPrefix(route.Path, resource.BasePath)
+			if resource.CanonicalActionName == "" && route.Verb == "GET" && canonicalRouteRegexp.MatchString(route.Path) {
+				resource.CanonicalActionName = action.Name
+			}
+		}
+	}
+}
+
+// canonicalRouteRegexp matches the relative routes of canonical actions.
+var canonicalRouteRegexp = regexp.MustCompile(`^/:[^/]+$`)
+
+// literalSegments returns the segments of the route path p before the first
+// wildcard.
+func literalSegments(p string) []string {
+	var segments []string
+	for _, s := range strings.Split(p, "/") {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			break
+		}
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// isIgnoredOperation returns true if one of the tags of operation is ignored.
+func (c *converter) isIgnoredOperation(operation *genswagger.Operation) bool {
+	for _, tag := range operation.Tags {
+		if matchAny(c.ignoredTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny returns true if name matches one of patterns, which have the
+// syntax of path.Match.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// decodePath converts a value of swagger.Paths to genswagger.Path. The values
+// are left as generic JSON objects by the decoder because paths may also
+// contain vendor extensions.
+func decodePath(v interface{}) (*genswagger.Path, error) {
+	if path, ok := v.(*genswagger.Path); ok {
+		return path, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var path genswagger.Path
+	if err := json.Unmarshal(data, &path); err != nil {
+		return nil, err
+	}
+	return &path, nil
+}
+
+type verbOperation struct {
+	verb      string
+	operation *genswagger.Operation
+}
+
+// pathOperations returns the operations defined in path with their HTTP verbs.
+func pathOperations(path *genswagger.Path) []verbOperation {
+	var operations []verbOperation
+	for _, o := range []verbOperation{
+		{"DELETE", path.Delete},
+		{"GET", path.Get},
+		{"HEAD", path.Head},
+		{"OPTIONS", path.Options},
+		{"PATCH", path.Patch},
+		{"POST", path.Post},
+		{"PUT", path.Put},
+	} {
+		if o.operation != nil {
+			operations = append(operations, o)
+		}
+	}
+	return operations
+}
+
+// resourceName returns the first segment of path. The root path belongs to
+// the resource named "root".
+func resourceName(path string) string {
+	for _, s := range strings.Split(path, "/") {
+		if s != "" && !strings.HasPrefix(s, "{") {
+			return s
+		}
+	}
+	return "root"
+}
+
+var pathParamRegexp = regexp.MustCompile(`{([^}]+)}`)
+
+// routePath converts the path template of swagger (e.g. /pets/{id}) to the
+// route syntax of goa (e.g. /pets/:id).
+func routePath(path string) string {
+	return pathParamRegexp.ReplaceAllString(path, ":$1")
+}
+
+// operationToAction converts operation to an action routed by verb and path.
+// pathParameters are the parameters shared by the operations of path.
+func (c *converter) operationToAction(verb, path string, pathParameters []*genswagger.Parameter, operation *genswagger.Operation) (*design.ActionDefinition, error) {
+	defer c.moveTo("paths", path, strings.ToLower(verb))()
+	action := &design.ActionDefinition{
+		Name:        operation.OperationID,
+		Description: operation.Description,
+		Schemes:     operation.Schemes,
+	}
+	if action.Name == "" {
+		action.Name = strings.ToLower(verb) + strings.Replace(pathParamRegexp.ReplaceAllString(path, "$1"), "/", "_", -1)
+	}
+	if name, ok := c.stringExtension(extensionAction); ok {
+		action.Name = name
+	}
+	c.action = action.Name
+	if action.Description == "" {
+		action.Description = operation.Summary
+	}
+	action.Metadata = c.metadataExtension()
+	c.record(action)
+	if operation.ExternalDocs != nil {
+		action.Docs = &design.DocsDefinition{
+			Description: operation.ExternalDocs.Description,
+			URL:         operation.ExternalDocs.URL,
+		}
+	}
+	action.Routes = []*design.RouteDefinition{
+		&design.RouteDefinition{
+			Verb:   verb,
+			Path:   routePath(path),
+			Parent: action,
+		},
+	}
+	for i, p := range pathParameters {
+		c.parameterPointers[p] = []string{"paths", path, "parameters", strconv.Itoa(i)}
+	}
+	for i, p := range operation.Parameters {
+		c.parameterPointers[p] = append(append([]string(nil), c.pointer...), "parameters", strconv.Itoa(i))
+	}
+	if err := c.operationParameters(action, pathParameters, operation); err != nil {
+		return nil, err
+	}
+	security, err := c.requirementsToSecurity(operation.Security, "security")
+	if err != nil {
+		return nil, err
+	}
+	action.Security = security
+	responses, err := c.operationResponses(action, operation)
+	if err != nil {
+		return nil, err
+	}
+	action.Responses = responses
+	return action, nil
+}
+
+// converter holds the state shared while converting a swagger.
+type converter struct {
+	definitions map[string]*genschema.JSONSchema
+	parameters  map[string]*genswagger.Parameter
+	produces    []string
+	consumes    []string
+	// securitySchemes holds the schemes that the security requirements of
+	// the operations refer to.
+	securitySchemes []*design.SecuritySchemeDefinition
+	types           map[string]*design.UserTypeDefinition
+	mediaTypes      map[string]*design.MediaTypeDefinition
+	naming          Naming
+	// typeNames maps the names of definitions to the names of their types.
+	typeNames map[string]string
+	// formats maps formats to the types and the validations of their
+	// values.
+	formats map[string]FormatMapping
+	// ignoredPaths and ignoredTags are the patterns of the paths and the
+	// tags of the operations that are not converted.
+	ignoredPaths []string
+	ignoredTags  []string
+	// document is the decoded document, which holds the vendor extensions.
+	document interface{}
+	// grouping is the strategy to group operations into resources.
+	grouping string
+	// defaultSecurity is the name of the security scheme of the API when
+	// swagger has no security requirements.
+	defaultSecurity string
+	// target is the version of goa DSL the design is rendered to.
+	target string
+	// inlining holds the definitions being inlined to detect cycles.
+	inlining []string
+	// action is the name of the action being converted, which names the
+	// types of its inline array items.
+	action string
+	// itemTypes maps the JSON pointers of inline object items to their
+	// types.
+	itemTypes map[string]*design.UserTypeDefinition
+	// origins holds the JSON pointers of the nodes that the definitions are
+	// converted from, which errors in the generated code refer to.
+	origins map[interface{}]string
+	// parameterPointers holds the tokens of the JSON pointers of the
+	// parameters, which are merged from several locations.
+	parameterPointers map[*genswagger.Parameter][]string
+	// pointer holds the tokens of the JSON pointer of the node being
+	// converted, which diagnostics refer to.
+	pointer     []string
+	diagnostics []Diagnostic
+}
+
+func newConverter(swagger genswagger.Swagger) *converter {
+	c := &converter{
+		definitions:       swagger.Definitions,
+		parameters:        swagger.Parameters,
+		produces:          swagger.Produces,
+		consumes:          swagger.Consumes,
+		types:             make(map[string]*design.UserTypeDefinition),
+		mediaTypes:        make(map[string]*design.MediaTypeDefinition),
+		itemTypes:         make(map[string]*design.UserTypeDefinition),
+		naming:            defaultNaming,
+		formats:           defaultFormats,
+		origins:           make(map[interface{}]string),
+		parameterPointers: make(map[*genswagger.Parameter][]string),
+	}
+	for name, p := range swagger.Parameters {
+		c.parameterPointers[p] = []string{"parameters", name}
+	}
+	return c
+}
+
+// at moves to the node at tokens relative to the node being converted. It
+// returns a function that moves back.
+func (c *converter) at(tokens ...string) func() {
+	n := len(c.pointer)
+	c.pointer = append(c.pointer, tokens...)
+	return func() { c.pointer = c.pointer[:n] }
+}
+
+// moveTo moves to the node at tokens from the root of the document. It
+// returns a function that moves back.
+func (c *converter) moveTo(tokens ...string) func() {
+	pointer := c.pointer
+	c.pointer = append([]string(nil), tokens...)
+	return func() { c.pointer = pointer }
+}
+
+// record records that definition is converted from the node being converted.
+func (c *converter) record(definition interface{}) {
+	c.origins[definition] = jsonPointer(c.pointer)
+}
+
+// warnf records a warning about the node being converted. Nodes that are
+// converted several times, such as query parameters, are warned about once.
+func (c *converter) warnf(format string, args ...interface{}) {
+	d := Diagnostic{
+		Severity: SeverityWarning,
+		Pointer:  jsonPointer(c.pointer),
+		Message:  fmt.Sprintf(format, args...),
+	}
+	for _, w := range c.diagnostics {
+		if w == d {
+			return
+		}
+	}
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+// errorf returns an error about the node being converted.
+func (c *converter) errorf(format string, args ...interface{}) error {
+	return &Error{
+		Pointer: jsonPointer(c.pointer),
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// definitionsToTypes converts the definitions of swagger to user types. Only
+// object schemas and schemas composed with allOf become types, the others are
+// inlined where they are referenced.
+func (c *converter) definitionsToTypes() error {
+	var names []string
+	for name, schema := range c.definitions {
+		restore := c.moveTo("definitions", name)
+		if isObjectSchema(schema) || c.allOf() != nil {
+			names = append(names, name)
+		}
+		restore()
+	}
+	sort.Strings(names)
+	definitions := make(map[string]string)
+	for _, name := range names {
+		typeName := c.typeName(name)
+		if definition, ok := definitions[typeName]; ok {
+			return &Error{
+				Pointer: jsonPointer([]string{"definitions", name}),
+				Message: fmt.Sprintf("type name %q is already used by definition %q", typeName, definition),
+			}
+		}
+		definitions[typeName] = name
+		c.types[name] = &design.UserTypeDefinition{
+			TypeName: typeName,
+		}
+	}
+	for _, name := range names {
+		t := c.types[name]
+		restore := c.moveTo("definitions", name)
+		attribute, err := c.schemaToAttribute(c.definitions[name])
+		if err != nil {
+			return err
+		}
+		c.record(t)
+		restore()
+		t.AttributeDefinition = attribute
+	}
+	return c.checkTypeCycles()
+}
+
+// typeName returns the name of the type of the definition named name. The
+// names set by the options take precedence over the x-goa-type-name vendor
+// extension of the definition.
+func (c *converter) typeName(name string) string {
+	if typeName, ok := c.typeNames[name]; ok {
+		return typeName
+	}
+	defer c.moveTo("definitions", name)()
+	if typeName, ok := c.stringExtension(extensionTypeName); ok {
+		return typeName
+	}
+	return name
+}
+
+// isObjectSchema returns true if schema describes an object with properties.
+func isObjectSchema(schema *genschema.JSONSchema) bool {
+	return schema.Ref == "" && (schema.Type == genschema.Object || schema.Type == "") && len(schema.Properties) > 0
+}
+
+// schemaToAttribute converts schema to an attribute. References to object
+// definitions become the corresponding user types, and schemas composed with
+// allOf are merged. Formats refine the types of primitives, and default values
+// and examples are converted to values of the types.
+func (c *converter) schemaToAttribute(schema *genschema.JSONSchema) (*design.AttributeDefinition, error) {
+	if schema.Ref != "" {
+		return c.referenceToAttribute(schema)
+	}
+	if allOf := c.allOf(); allOf != nil {
+		return c.allOfToAttribute(schema, allOf)
+	}
+	attribute := &design.AttributeDefinition{
+		Description: schema.Description,
+		Validation:  schemaToValidation(schema),
+		Metadata:    c.metadataExtension(),
+	}
+	c.record(attribute)
+	switch schema.Type {
+	case genschema.Boolean:
+		attribute.Type = design.Boolean
+	case genschema.Integer:
+		attribute.Type = design.Integer
+	case genschema.Number:
+		attribute.Type = design.Number
+	case genschema.String:
+		attribute.Type = design.String
+	case genschema.File:
+		attribute.Type = design.File
+	case genschema.Array:
+		array, err := c.itemsToArray(schema)
+		if err != nil {
+			return nil, err
+		}
+		attribute.Type = array
+	case genschema.Object, "":
+		if len(schema.Properties) == 0 {
+			attribute.Type = design.Any
+			if schema.AdditionalProperties {
+				hash, err := c.additionalPropertiesToHash()
+				if err != nil {
+					return nil, err
+				}
+				attribute.Type = hash
+			}
+			break
+		}
+		if schema.AdditionalProperties {
+			back := c.at("additionalProperties")
+			c.warnf("additional properties cannot be combined with fixed properties, the open-ended keys are lost")
+			back()
+		}
+		object, err := c.propertiesToObject(schema.Properties)
+		if err != nil {
+			return nil, err
+		}
+		attribute.Type = object
+	default:
+		attribute.Type = design.Any
+	}
+	c.applyFormat(attribute, schema)
+	c.applyValues(attribute)
+	return attribute, nil
+}
+
+// propertiesToObject converts the properties of the schema being converted
+// to an object.
+func (c *converter) propertiesToObject(properties map[string]*genschema.JSONSchema) (design.Object, error) {
+	object := make(design.Object)
+	for name, property := range properties {
+		back := c.at("properties", name)
+		a, err := c.schemaToAttribute(property)
+		if err != nil {
+			return nil, err
+		}
+		back()
+		object[name] = a
+	}
+	return object, nil
+}
+
+// referenceToAttribute converts a schema that refers to a definition.
+func (c *converter) referenceToAttribute(schema *genschema.JSONSchema) (*design.AttributeDefinition, error) {
+	name, ok := definitionName(schema.Ref)
+	if !ok {
+		return nil, c.errorf("unresolved reference %q", schema.Ref)
+	}
+	if t, ok := c.types[name]; ok {
+		attribute := &design.AttributeDefinition{
+			Type:        t,
+			Description: schema.Description,
+			Metadata:    c.metadataExtension(),
+		}
+		c.record(attribute)
+		return attribute, nil
+	}
+	attribute, err := c.inlineDefinition(schema.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Description != "" {
+		attribute.Description = schema.Description
+	}
+	return attribute, nil
+}
+
+// inlineDefinition converts the definition that ref refers to, as if it was
+// defined in place of the reference.
+func (c *converter) inlineDefinition(ref string) (*design.AttributeDefinition, error) {
+	name, ok := definitionName(ref)
+	definition, found := c.definitions[name]
+	if !ok || !found {
+		return nil, c.errorf("unresolved reference %q", ref)
+	}
+	for i, inlining := range c.inlining {
+		if inlining == name {
+			return nil, c.errorf("circular reference: %s", strings.Join(append(c.inlining[i:], name), " -> "))
+		}
+	}
+	c.inlining = append(c.inlining, name)
+	defer func() { c.inlining = c.inlining[:len(c.inlining)-1] }()
+	defer c.moveTo("definitions", name)()
+	return c.schemaToAttribute(definition)
+}
+
+// checkTypeCycles returns an error if the types refer to each other
+// circularly, which the generated variables cannot express.
+func (c *converter) checkTypeCycles() error {
+	var names []string
+	for name := range c.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	visited := make(map[string]bool)
+	var visit func(t *design.UserTypeDefinition, path []string) error
+	visit = func(t *design.UserTypeDefinition, path []string) error {
+		for i, p := range path {
+			if p == t.TypeName {
+				return &Error{
+					Pointer: c.origins[t],
+					Message: fmt.Sprintf("circular reference: %s", strings.Join(append(path[i:], t.TypeName), " -> ")),
+				}
+			}
+		}
+		if visited[t.TypeName] {
+			return nil
+		}
+		path = append(path, t.TypeName)
+		for _, dependency := range typeDependencies(t.AttributeDefinition) {
+			if err := visit(dependency, path); err != nil {
+				return err
+			}
+		}
+		visited[t.TypeName] = true
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(c.types[name], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeDependencies returns the user types that attribute refers to.
+func typeDependencies(attribute *design.AttributeDefinition) []*design.UserTypeDefinition {
+	if attribute == nil {
+		return nil
+	}
+	switch t := attribute.Type.(type) {
+	case *design.UserTypeDefinition:
+		return []*design.UserTypeDefinition{t}
+	case *design.Array:
+		return typeDependencies(t.ElemType)
+	case *design.Hash:
+		return append(typeDependencies(t.KeyType), typeDependencies(t.ElemType)...)
+	case design.Object:
+		var dependencies []*design.UserTypeDefinition
+		if reference, ok := attribute.Reference.(*design.UserTypeDefinition); ok {
+			dependencies = append(dependencies, reference)
+		}
+		for _, a := range t {
+			dependencies = append(dependencies, typeDependencies(a)...)
+		}
+		return dependencies
+	}
+	return nil
+}
+
+// schemaToValidation returns the validations of schema. The bounds of the
+// items of arrays become MinLength and MaxLength like the bounds of strings.
+// It returns nil if schema has no validations.
+func schemaToValidation(schema *genschema.JSONSchema) *dslengine.ValidationDefinition {
+	validation := &dslengine.ValidationDefinition{
+		Values:    schema.Enum,
+		Pattern:   schema.Pattern,
+		Minimum:   schema.Minimum,
+		Maximum:   schema.Maximum,
+		MinLength: schema.MinLength,
+		MaxLength: schema.MaxLength,
+		Required:  schema.Required,
+	}
+	if schema.MinItems != nil {
+		validation.MinLength = schema.MinItems
+	}
+	if schema.MaxItems != nil {
+		validation.MaxLength = schema.MaxItems
+	}
+	if validation.Values == nil && validation.Pattern == "" &&
+		validation.Minimum == nil && validation.Maximum == nil &&
+		validation.MinLength == nil && validation.MaxLength == nil &&
+		validation.Required == nil {
+		return nil
+	}
+	return validation
+}
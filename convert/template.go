@@ -0,0 +1,588 @@
+package convert
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+const (
+	goHeaderT = `
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+`
+	allT = `
+{{template "goHeader" .}}
+{{template "api" .}}
+
+{{template "securityScheme" .}}
+
+{{template "resource" .}}
+
+{{template "type" .}}
+
+{{template "mediaType" .}}
+`
+
+	// Files of a design package.
+	apiFileT = `
+{{template "goHeader" .}}
+{{template "api" .}}
+
+{{template "securityScheme" .}}
+`
+	mediaTypeFileT = `
+{{template "goHeader" .}}
+{{template "mediaType" .}}
+`
+	resourceFileT = `
+{{template "goHeader" .}}
+{{template "resource" .}}
+`
+	typeFileT = `
+{{template "goHeader" .}}
+{{template "type" .}}
+`
+
+	// Components that have single value.
+	basePathT            = `{{if .BasePath}}BasePath({{printf "%q" .BasePath}}){{end}}`                                  // This template expects APIDefinition or ResourceDefinition.
+	canonicalActionNameT = `{{if .CanonicalActionName}}CanonicalActionName({{printf "%q" .CanonicalActionName}}){{end}}` // This template expects ResourceDefinition.
+	contentTypeT         = `{{if .ContentType}}ContentType({{printf "%q" .ContentType}}){{end}}`                         // This template expects MediaTypeDefinition.
+	credentialsT         = `{{if .Credentials}}Credentials(){{end}}`                                                     // This template expects CORSDefinition.
+	defaultT             = `{{with literal .DefaultValue}}Default({{.}}){{end}}`                                         // This template expects AttributeDefinition.
+	descriptionT         = `{{if .Description}}Description({{printf "%q" .Description}}){{end}}`                         // This template expects APIDefinition or DocsDefinition or ResourceDefinition or ResponseDefinition.
+	emailT               = `{{if .Email}}Email({{printf "%q" .Email}}){{end}}`                                           // This template expects ContactDefinition.
+	exampleT             = `{{with literal .Example}}Example({{.}}){{end}}`                                              // This template expects AttributeDefinition.
+	formatT              = `{{if .Format}}Format({{printf "%q" .Format}}){{end}}`                                        // This template expects ValidationDefinition.
+	functionT            = `{{if .Function}}Function({{printf "%q" .Function}}){{end}}`                                  // This template expects EncodingDefinition.
+	hostT                = `{{if .Host}}Host({{printf "%q" .Host}}){{end}}`                                              // This template expects APIDefinition.
+	maxAgeT              = `{{if .MaxAge}}MaxAge({{.MaxAge}}){{end}}`                                                    // This template expects CORSDefinition.
+	maxLengthT           = `{{if .MaxLength}}MaxLength({{.MaxLength}}){{end}}`                                           // This template expects ValidationDefinition.
+	maximumT             = `{{if .Maximum}}Maximum({{.Maximum}}){{end}}`                                                 // This template expects ValidationDefinition.
+	minLengthT           = `{{if .MinLength}}MinLength({{.MinLength}}){{end}}`                                           // This template expects ValidationDefinition.
+	minimumT             = `{{if .Minimum}}Minimum({{.Minimum}}){{end}}`                                                 // This template expects ValidationDefinition.
+	nameT                = `{{if .Name}}Name({{printf "%q" .Name}}){{end}}`                                              // This template expects APIDefinition or ContactDefinition or LicenseDefinition.
+	noExampleT           = `{{if .NoExamples}}NoExample(){{end}}`                                                        // This template expects APIDefinition.
+	packageT             = `{{if .PackagePath}}Package({{printf "%q" .PackagePath}}){{end}}`                             // This template expects EncodingDefinition.
+	parentT              = `{{if .ParentName}}Parent({{printf "%q" .ParentName}}){{end}}`                                // This template expects ResourceDefinition.
+	patternT             = `{{if .Pattern}}Pattern({{printf "%q" .Pattern}}){{end}}`                                     // This template expects ValidationDefinition.
+	referenceT           = `{{if .Reference}}Reference({{dataType .Reference}}){{end}}`                                  // This template expects AttributeDefinition.
+	statusT              = `{{if .Status}}Status({{.Status}}){{end}}`                                                    // This template expects ResponseDefinition.
+	termsOfServiceT      = `{{if .TermsOfService}}TermsOfService({{printf "%q" .TermsOfService}}){{end}}`                // This template expects APIDefinition.
+	titleT               = `{{if .Title}}Title({{printf "%q" .Title}}){{end}}`                                           // This template expects APIDefinition.
+	typeNameT            = `{{if .TypeName}}TypeName({{printf "%q" .TypeName}}){{end}}`                                  // This template expects MediaTypeDefinition or UserTypeDefinition.
+	urlT                 = `{{if .URL}}URL({{printf "%q" .URL}}){{end}}`                                                 // This template expects ContactDefinition or LicenseDefinition or DocsDefinition.
+	versionT             = `{{if .Version}}Version({{printf "%q" .Version}}){{end}}`                                     // This template expects APIDefinition.
+
+	// Components that have multiple values.
+	enumT = `{{if .Values}}Enum({{if (eq (len .Values) 1)}}{{range .Values}}{{printf "%#v" .}}{{end}}){{else}}
+{{range .Values}}{{printf "%#v" .}},
+{{end}}){{end}}{{end}}` // This template expects ValidationDefinition.
+	exposeT = `{{if .Exposed}}Expose({{if (eq (len .Exposed) 1)}}{{range .Exposed}}{{printf "%q" .}}{{end}}){{else}}
+{{range .Exposed}}{{printf "%q" .}},
+{{end}}){{end}}{{end}}` // This template expects CORSDefinition.
+	metadataT = `{{if .Metadata}}{{range $i, $name := keys .Metadata}}{{if $i}}
+{{end}}Metadata({{printf "%q" $name}}{{range index $.Metadata $name}}, {{printf "%q" .}}{{end}}){{end}}{{end}}` // This template expects APIDefinition or ActionDefinition or AttributeDefinition or MediaTypeDefinition.
+	methodsT = `{{if .Methods}}Methods({{if (eq (len .Methods) 1)}}{{range .Methods}}{{printf "%q" .}}{{end}}){{else}}
+{{range .Methods}}{{printf "%q" .}},
+{{end}}){{end}}{{end}}` // This template expects CORSDefinition.
+	requiredT = `{{if .Required}}Required({{if (eq (len .Required) 1)}}{{range .Required}}{{printf "%q" .}}{{end}}){{else}}
+{{range .Required}}{{printf "%q" .}},
+{{end}}){{end}}{{end}}` // This template expects ValidationDefinition.
+	schemeT = `{{if .Schemes}}Scheme({{if (eq (len .Schemes) 1)}}{{range .Schemes}}{{printf "%q" .}}{{end}}){{else}}
+{{range .Schemes}}{{printf "%q" .}},
+{{end}}){{end}}{{end}}` // This template expects APIDefinition or ResourceDefinition or ActionDefinition.
+	scopeT = `{{if .Scopes}}{{range $i, $name := keys .Scopes}}{{if $i}}
+{{end}}Scope({{printf "%q" $name}}{{with index $.Scopes $name}}, {{printf "%q" .}}{{end}}){{end}}{{end}}` // This template expects SecuritySchemeDefinition.
+
+	// Containers.
+	actionT = `{{if .Actions}}{{$actions := .Actions}}{{$keys := keys .Actions}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $actions .}}Action({{printf "%q" .Name}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if .Docs}}{{template "docs" .}}
+{{end}}{{if .Schemes}}{{template "scheme" .}}
+{{end}}{{if .Routes}}{{template "routing" .}}
+{{end}}{{if .Security}}{{template "security" .}}
+{{end}}{{if .Params}}{{template "params" .Params}}
+{{end}}{{if .Headers}}{{template "headers" .Headers}}
+{{end}}{{if .Payload}}{{template "payload" .}}
+{{end}}{{if .PayloadMultipart}}MultipartForm()
+{{end}}{{if .Responses}}{{template "response" .}}
+{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}){{end}}{{end}}{{end}}`
+	arrayOfT = `ArrayOf({{dataType .Type}}, func() {
+{{template "validation" .Validation}}})` // This template expects AttributeDefinition, the elements of an array.
+	attributeT = `{{if .Type}}{{$attributes := .Type.ToObject}}{{if $attributes}}{{$keys := keys $attributes}}{{range $name := $keys}}{{if (not (eq (index $keys 0) $name))}}
+{{end}}{{with index $attributes $name}}Attribute({{printf "%q" $name}}{{template "attributeArgs" .}}){{end}}{{end}}{{end}}{{end}}`
+	attributeArgsT = `{{$type := dataType .Type}}{{if $type}}, {{with validatedElem .Type}}{{template "arrayOf" .}}{{else}}{{$type}}{{end}}{{end}}{{if (or .Description .Validation .Metadata (literal .DefaultValue) (literal .Example) (not $type))}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if (not $type)}}{{template "attribute" .}}
+{{end}}{{with .Validation}}{{template "validation" .}}{{end}}{{if (literal .DefaultValue)}}{{template "default" .}}
+{{end}}{{if (literal .Example)}}{{template "example" .}}
+{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}{{end}}` // This template expects AttributeDefinition.
+	apiT = `{{if .}}var _ = API({{if .Name}}{{printf "%q" .Name}}{{else}}""{{end}}, func() {
+{{if .Title}}{{template "title" .}}
+{{end}}{{if .Description}}{{template "description" .}}
+{{end}}{{if .Version}}{{template "version" .}}
+{{end}}{{if .TermsOfService}}{{template "termsOfService" .}}
+{{end}}{{if .Contact}}{{template "contact" .}}
+{{end}}{{if .License}}{{template "license" .}}
+{{end}}{{if .Docs}}{{template "docs" .}}
+{{end}}{{if .Host}}{{template "host" .}}
+{{end}}{{if .Schemes}}{{template "scheme" .}}
+{{end}}{{if .BasePath}}{{template "basePath" .}}
+{{end}}{{if .Params}}{{template "params" .Params}}
+{{end}}{{if .Origins}}{{template "origin" .}}
+{{end}}{{if .Consumes}}{{template "consumes" .}}
+{{end}}{{if .Produces}}{{template "produces" .}}
+{{end}}{{if .Security}}{{if .Security.Scheme.SchemeName}}{{template "security" .}}
+{{end}}{{end}}{{if .NoExamples}}{{template "noExample" .}}
+{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}){{end}}`
+	connectT  = `{{if .Verb}}{{if (eq .Verb "CONNECT")}}CONNECT({{printf "%q" .Path}}){{end}}{{end}}`
+	consumesT = `{{if .Consumes}}{{range $index, $element := .Consumes}}{{with $element}}{{if (not (eq $index 0))}}
+{{end}}Consumes({{if (or (gt (len .MIMETypes) 1) .Function .PackagePath)}}
+{{range .MIMETypes}}{{printf "%q" .}},
+{{end}}{{if (or .Function .PackagePath)}}func() {
+{{if .Function}}{{template "function" .}}
+{{end}}{{if .PackagePath}}{{template "package" .}}
+{{end}}},
+{{end}}{{else}}{{range .MIMETypes}}{{printf "%q" .}}{{end}}{{end}}){{end}}{{end}}{{end}}`
+	contactT = `{{if .Contact}}{{with .Contact}}Contact(func() {
+{{if .Name}}{{template "name" .}}
+{{end}}{{if .Email}}{{template "email" .}}
+{{end}}{{if .URL}}{{template "url" .}}
+{{end}}}){{end}}{{end}}`
+	deleteT = `{{if .Verb}}{{if (eq .Verb "DELETE")}}DELETE({{printf "%q" .Path}}){{end}}{{end}}`
+	docsT   = `{{if .Docs}}{{with .Docs}}Docs(func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if .URL}}{{template "url" .}}
+{{end}}}){{end}}{{end}}`
+	flowT    = `{{if (eq .Flow "accessCode")}}AccessCodeFlow({{printf "%q" .AuthorizationURL}}, {{printf "%q" .TokenURL}}){{else if (eq .Flow "implicit")}}ImplicitFlow({{printf "%q" .AuthorizationURL}}){{else if (eq .Flow "password")}}PasswordFlow({{printf "%q" .TokenURL}}){{else if (eq .Flow "application")}}ApplicationFlow({{printf "%q" .TokenURL}}){{end}}` // This template expects SecuritySchemeDefinition.
+	getT     = `{{if .Verb}}{{if (eq .Verb "GET")}}GET({{printf "%q" .Path}}){{end}}{{end}}`
+	headT    = `{{if .Verb}}{{if (eq .Verb "HEAD")}}HEAD({{printf "%q" .Path}}){{end}}{{end}}`
+	headersT = `{{if .Type}}{{$headers := .Type.ToObject}}{{if $headers}}Headers(func() {
+{{$keys := keys $headers}}{{range $name := $keys}}{{with index $headers $name}}Header({{printf "%q" $name}}{{template "attributeArgs" .}})
+{{end}}{{end}}{{with .Validation}}{{template "validation" .}}{{end}}}){{end}}{{end}}` // This template expects AttributeDefinition.
+	licenseT = `{{if .License}}{{with .License}}License(func() {
+{{if .Name}}{{template "name" .}}
+{{end}}{{if .URL}}{{template "url" .}}
+{{end}}}){{end}}{{end}}`
+	mediaT     = `{{if .Type}}Media({{dataType .Type}}{{if .ViewName}}, {{printf "%q" .ViewName}}{{end}}){{end}}` // This template expects ResponseDefinition.
+	mediaTypeT = `{{if .MediaTypes}}{{$mediaTypes := .MediaTypes}}{{$keys := keys .MediaTypes}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $mediaTypes .}}var {{goify .TypeName}} = MediaType({{printf "%q" .Identifier}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if .TypeName}}{{template "typeName" .}}
+{{end}}{{if .ContentType}}{{template "contentType" .}}
+{{end}}{{if .Type}}Attributes(func() {
+{{if .Type.ToObject}}{{template "attribute" .AttributeDefinition}}
+{{end}}{{with .Validation}}{{template "validation" .}}{{end}}})
+{{end}}{{if .Views}}{{template "view" .}}
+{{end}}{{with .AttributeDefinition}}{{if (literal .Example)}}{{template "example" .}}
+{{end}}{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}){{end}}{{end}}{{end}}`
+	optionsT = `{{if .Verb}}{{if (eq .Verb "OPTIONS")}}OPTIONS({{printf "%q" .Path}}){{end}}{{end}}`
+	originT  = `{{if .Origins}}{{$origins := .Origins}}{{$keys := keys .Origins}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $origins .}}Origin({{printf "%q" .Origin}}, func() {
+{{if .Headers}}Headers(
+{{range .Headers}}{{printf "%q" .}},
+{{end}})
+{{end}}{{if .Methods}}{{template "methods" .}}
+{{end}}{{if .Exposed}}{{template "expose" .}}
+{{end}}{{if .MaxAge}}{{template "maxAge" .}}
+{{end}}{{if .Credentials}}{{template "credentials" .}}
+{{end}}}){{end}}{{end}}{{end}}`
+	patchT  = `{{if .Verb}}{{if (eq .Verb "PATCH")}}PATCH({{printf "%q" .Path}}){{end}}{{end}}`
+	paramsT = `{{if .Type}}{{$params := .Type.ToObject}}{{if $params}}Params(func() {
+{{$keys := keys $params}}{{range $name := $keys}}{{with index $params $name}}Param({{printf "%q" $name}}{{template "attributeArgs" .}})
+{{end}}{{end}}{{with .Validation}}{{template "validation" .}}{{end}}}){{end}}{{end}}` // This template expects AttributeDefinition.
+	payloadT  = `{{if .Payload}}{{with .Payload}}{{if $.PayloadOptional}}OptionalPayload{{else}}Payload{{end}}({{if .TypeName}}{{goify .TypeName}}{{else}}{{dataType .Type}}{{end}}){{end}}{{end}}`
+	postT     = `{{if .Verb}}{{if (eq .Verb "POST")}}POST({{printf "%q" .Path}}){{end}}{{end}}`
+	putT      = `{{if .Verb}}{{if (eq .Verb "PUT")}}PUT({{printf "%q" .Path}}){{end}}{{end}}`
+	producesT = `{{if .Produces}}{{range $index, $element := .Produces}}{{with $element}}{{if (not (eq $index 0))}}
+{{end}}Produces({{if (or (gt (len .MIMETypes) 1) .Function .PackagePath)}}
+{{range .MIMETypes}}{{printf "%q" .}},
+{{end}}{{if (or .Function .PackagePath)}}func() {
+{{if .Function}}{{template "function" .}}
+{{end}}{{if .PackagePath}}{{template "package" .}}
+{{end}}},
+{{end}}{{else}}{{range .MIMETypes}}{{printf "%q" .}}{{end}}{{end}}){{end}}{{end}}{{end}}`
+	resourceT = `{{if .Resources}}{{$resources := .Resources}}{{$keys := keys .Resources}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $resources .}}var _ = Resource({{printf "%q" .Name}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if .BasePath}}{{template "basePath" .}}
+{{end}}{{if .CanonicalActionName}}{{template "canonicalActionName" .}}
+{{end}}{{if .ParentName}}{{template "parent" .}}
+{{end}}{{if .Schemes}}{{template "scheme" .}}
+{{end}}{{if .Actions}}{{template "action" .}}
+{{end}}}){{end}}{{end}}{{end}}`
+	responseT = `{{if .Responses}}{{$responses := .Responses}}{{$keys := keys .Responses}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $responses .}}{{$custom := (and .Status (not .Standard))}}Response({{if $custom}}{{printf "%q" .Name}}{{else}}{{.Name}}{{end}}{{if (and .Type (not .ViewName))}}, {{dataType .Type}}{{end}}{{if (or $custom .Description .ViewName .Headers)}}, func() {
+{{if $custom}}{{template "status" .}}
+{{end}}{{if .Description}}{{template "description" .}}
+{{end}}{{if .ViewName}}{{template "media" .}}
+{{end}}{{if .Headers}}{{template "headers" .Headers}}
+{{end}}}){{else}}){{end}}{{end}}{{end}}{{end}}`
+	routingT = `{{if .Routes}}Routing({{if (eq (len .Routes) 1)}}{{range .Routes}}{{template "connect" .}}{{template "delete" .}}{{template "get" .}}{{template "head" .}}{{template "options" .}}{{template "patch" .}}{{template "post" .}}{{template "put" .}}{{template "trace" .}}{{end}}){{else}}
+{{range .Routes}}{{if (eq .Verb "CONNECT")}}{{template "connect" .}},
+{{end}}{{if (eq .Verb "DELETE")}}{{template "delete" .}},
+{{end}}{{if (eq .Verb "GET")}}{{template "get" .}},
+{{end}}{{if (eq .Verb "HEAD")}}{{template "head" .}},
+{{end}}{{if (eq .Verb "OPTIONS")}}{{template "options" .}},
+{{end}}{{if (eq .Verb "PATCH")}}{{template "patch" .}},
+{{end}}{{if (eq .Verb "POST")}}{{template "post" .}},
+{{end}}{{if (eq .Verb "PUT")}}{{template "put" .}},
+{{end}}{{if (eq .Verb "TRACE")}}{{template "trace" .}},
+{{end}}{{end}}){{end}}{{end}}`
+	securityT = `{{with .Security}}{{if .Scheme.SchemeName}}Security({{printf "%q" .Scheme.SchemeName}}{{if .Scopes}}, func() {
+{{range .Scopes}}Scope({{printf "%q" .}})
+{{end}}}{{end}}){{else}}NoSecurity(){{end}}{{end}}` // This template expects APIDefinition or ActionDefinition.
+	securitySchemeT = `{{if .SecuritySchemes}}{{range $i, $scheme := .SecuritySchemes}}{{if $i}}
+{{end}}var _ = {{if (eq .Type "basic")}}BasicAuthSecurity{{else if (eq .Type "apiKey")}}APIKeySecurity{{else}}OAuth2Security{{end}}({{printf "%q" .SchemeName}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if (eq .Type "apiKey")}}{{if (eq .In "query")}}Query{{else}}Header{{end}}({{printf "%q" .Name}})
+{{end}}{{if .Flow}}{{template "flow" .}}
+{{end}}{{if .Scopes}}{{template "scope" .}}
+{{end}}}){{end}}{{end}}` // This template expects APIDefinition.
+	traceT = `{{if .Verb}}{{if (eq .Verb "TRACE")}}TRACE({{printf "%q" .Path}}){{end}}{{end}}`
+	typeT  = `{{if .Types}}{{$types := .Types}}{{$keys := keys .Types}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $types .}}var {{goify .TypeName}} = Type({{printf "%q" .TypeName}}, func() {
+{{with .AttributeDefinition}}{{if .Description}}{{template "description" .}}
+{{end}}{{if .Reference}}{{template "reference" .}}
+{{end}}{{if .Type}}{{if .Type.ToObject}}{{template "attribute" .}}
+{{end}}{{end}}{{with .Validation}}{{template "validation" .}}{{end}}{{if (literal .Example)}}{{template "example" .}}
+{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}{{end}}}){{end}}{{end}}{{end}}`
+	viewT = `{{if .Views}}{{$views := .Views}}{{$keys := keys .Views}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $views .}}View({{printf "%q" .Name}}, func() {
+{{if .Type}}{{$attributes := .Type.ToObject}}{{range $name := keys $attributes}}Attribute({{printf "%q" $name}})
+{{end}}{{end}}}){{end}}{{end}}{{end}}` // This template expects MediaTypeDefinition.
+	validationT = `{{if .Values}}{{template "enum" .}}
+{{end}}{{if .Format}}{{template "format" .}}
+{{end}}{{if .Pattern}}{{template "pattern" .}}
+{{end}}{{if .Minimum}}{{template "minimum" .}}
+{{end}}{{if .Maximum}}{{template "maximum" .}}
+{{end}}{{if .MinLength}}{{template "minLength" .}}
+{{end}}{{if .MaxLength}}{{template "maxLength" .}}
+{{end}}{{if .Required}}{{template "required" .}}
+{{end}}` // This template expects ValidationDefinition.
+)
+
+var (
+	tmpl *template.Template
+)
+
+// templateExt is the extension of the files of templates.
+const templateExt = ".tmpl"
+
+// Targets of the generated DSL.
+const (
+	TargetV1 = "v1"
+	TargetV3 = "v3"
+)
+
+// targetTemplate returns the templates that generate the DSL of target.
+func targetTemplate(target string) (*template.Template, error) {
+	switch target {
+	case "", TargetV1:
+		return tmpl, nil
+	case TargetV3:
+		return tmplV3, nil
+	default:
+		return nil, fmt.Errorf("unsupported target %q", target)
+	}
+}
+
+// Templates returns the sources of the templates that generate the DSL of
+// target by name. They are the starting point of the templates that replace
+// them with RenderOptions.TemplateDir.
+func Templates(target string) (map[string]string, error) {
+	t, err := targetTemplate(target)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string)
+	for _, tt := range t.Templates() {
+		if tt.Tree == nil || tt.Tree.Root == nil {
+			continue
+		}
+		sources[tt.Name()] = tt.Tree.Root.String()
+	}
+	return sources, nil
+}
+
+// overrideTemplates returns a copy of t whose templates are replaced with
+// the .tmpl files of dir named after them.
+func overrideTemplates(t *template.Template, dir string) (*template.Template, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return nil, err
+	}
+	overridden, err := t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), templateExt)
+		if t.Lookup(name) == nil {
+			return nil, fmt.Errorf("%s: unknown template %q", file, name)
+		}
+		source, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := replaceTemplate(overridden, name, string(source)); err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err)
+		}
+	}
+	return overridden, nil
+}
+
+// replaceTemplate parses source as the template of t named name and returns
+// it. Unlike Template.Parse, it replaces the existing template even when source
+// is empty or only holds comments, so that such templates generate nothing.
+func replaceTemplate(t *template.Template, name, source string) (*template.Template, error) {
+	parsed, err := t.New(name).Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	replaced := t.Lookup(name)
+	replaced.Tree = parsed.Tree
+	return replaced, nil
+}
+
+func init() {
+	tmpl = template.New("")
+
+	tmpl = tmpl.Funcs(template.FuncMap{
+		"keys": func(x interface{}) interface{} {
+			switch t := x.(type) {
+			case map[string]*design.ActionDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case map[string]*design.CORSDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case dslengine.MetadataDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case map[string]*design.MediaTypeDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case map[string]*design.ResourceDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case map[string]*design.ResponseDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case design.Object:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case map[string]string:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case map[string]*design.UserTypeDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			case map[string]*design.ViewDefinition:
+				var keys []string
+				for k := range t {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			default:
+				return nil
+			}
+		},
+		"dataType":      dataType,
+		"goify":         identifier,
+		"literal":       literal,
+		"validatedElem": validatedElem,
+	})
+
+	tmpl = template.Must(tmpl.New("goHeader").Parse(goHeaderT))
+	tmpl = template.Must(tmpl.New("all").Parse(allT))
+
+	// Files of a design package.
+	tmpl = template.Must(tmpl.New("apiFile").Parse(apiFileT))
+	tmpl = template.Must(tmpl.New("mediaTypeFile").Parse(mediaTypeFileT))
+	tmpl = template.Must(tmpl.New("resourceFile").Parse(resourceFileT))
+	tmpl = template.Must(tmpl.New("typeFile").Parse(typeFileT))
+
+	// Components that have single value.
+	tmpl = template.Must(tmpl.New("basePath").Parse(basePathT))
+	tmpl = template.Must(tmpl.New("canonicalActionName").Parse(canonicalActionNameT))
+	tmpl = template.Must(tmpl.New("contentType").Parse(contentTypeT))
+	tmpl = template.Must(tmpl.New("credentials").Parse(credentialsT))
+	tmpl = template.Must(tmpl.New("default").Parse(defaultT))
+	tmpl = template.Must(tmpl.New("description").Parse(descriptionT))
+	tmpl = template.Must(tmpl.New("email").Parse(emailT))
+	tmpl = template.Must(tmpl.New("example").Parse(exampleT))
+	tmpl = template.Must(tmpl.New("format").Parse(formatT))
+	tmpl = template.Must(tmpl.New("function").Parse(functionT))
+	tmpl = template.Must(tmpl.New("host").Parse(hostT))
+	tmpl = template.Must(tmpl.New("maxAge").Parse(maxAgeT))
+	tmpl = template.Must(tmpl.New("maxLength").Parse(maxLengthT))
+	tmpl = template.Must(tmpl.New("maximum").Parse(maximumT))
+	tmpl = template.Must(tmpl.New("minLength").Parse(minLengthT))
+	tmpl = template.Must(tmpl.New("minimum").Parse(minimumT))
+	tmpl = template.Must(tmpl.New("name").Parse(nameT))
+	tmpl = template.Must(tmpl.New("noExample").Parse(noExampleT))
+	tmpl = template.Must(tmpl.New("package").Parse(packageT))
+	tmpl = template.Must(tmpl.New("parent").Parse(parentT))
+	tmpl = template.Must(tmpl.New("pattern").Parse(patternT))
+	tmpl = template.Must(tmpl.New("reference").Parse(referenceT))
+	tmpl = template.Must(tmpl.New("status").Parse(statusT))
+	tmpl = template.Must(tmpl.New("termsOfService").Parse(termsOfServiceT))
+	tmpl = template.Must(tmpl.New("title").Parse(titleT))
+	tmpl = template.Must(tmpl.New("typeName").Parse(typeNameT))
+	tmpl = template.Must(tmpl.New("url").Parse(urlT))
+	tmpl = template.Must(tmpl.New("version").Parse(versionT))
+
+	// Components that have multiple values.
+	tmpl = template.Must(tmpl.New("enum").Parse(enumT))
+	tmpl = template.Must(tmpl.New("expose").Parse(exposeT))
+	tmpl = template.Must(tmpl.New("metadata").Parse(metadataT))
+	tmpl = template.Must(tmpl.New("methods").Parse(methodsT))
+	tmpl = template.Must(tmpl.New("required").Parse(requiredT))
+	tmpl = template.Must(tmpl.New("scheme").Parse(schemeT))
+	tmpl = template.Must(tmpl.New("scope").Parse(scopeT))
+
+	// Containers.
+	tmpl = template.Must(tmpl.New("action").Parse(actionT))
+	tmpl = template.Must(tmpl.New("api").Parse(apiT))
+	tmpl = template.Must(tmpl.New("arrayOf").Parse(arrayOfT))
+	tmpl = template.Must(tmpl.New("attribute").Parse(attributeT))
+	tmpl = template.Must(tmpl.New("attributeArgs").Parse(attributeArgsT))
+	tmpl = template.Must(tmpl.New("connect").Parse(connectT))
+	tmpl = template.Must(tmpl.New("consumes").Parse(consumesT))
+	tmpl = template.Must(tmpl.New("contact").Parse(contactT))
+	tmpl = template.Must(tmpl.New("delete").Parse(deleteT))
+	tmpl = template.Must(tmpl.New("docs").Parse(docsT))
+	tmpl = template.Must(tmpl.New("flow").Parse(flowT))
+	tmpl = template.Must(tmpl.New("get").Parse(getT))
+	tmpl = template.Must(tmpl.New("head").Parse(headT))
+	tmpl = template.Must(tmpl.New("headers").Parse(headersT))
+	tmpl = template.Must(tmpl.New("license").Parse(licenseT))
+	tmpl = template.Must(tmpl.New("media").Parse(mediaT))
+	tmpl = template.Must(tmpl.New("mediaType").Parse(mediaTypeT))
+	tmpl = template.Must(tmpl.New("options").Parse(optionsT))
+	tmpl = template.Must(tmpl.New("origin").Parse(originT))
+	tmpl = template.Must(tmpl.New("patch").Parse(patchT))
+	tmpl = template.Must(tmpl.New("params").Parse(paramsT))
+	tmpl = template.Must(tmpl.New("payload").Parse(payloadT))
+	tmpl = template.Must(tmpl.New("post").Parse(postT))
+	tmpl = template.Must(tmpl.New("put").Parse(putT))
+	tmpl = template.Must(tmpl.New("produces").Parse(producesT))
+	tmpl = template.Must(tmpl.New("resource").Parse(resourceT))
+	tmpl = template.Must(tmpl.New("response").Parse(responseT))
+	tmpl = template.Must(tmpl.New("routing").Parse(routingT))
+	tmpl = template.Must(tmpl.New("security").Parse(securityT))
+	tmpl = template.Must(tmpl.New("securityScheme").Parse(securitySchemeT))
+	tmpl = template.Must(tmpl.New("trace").Parse(traceT))
+	tmpl = template.Must(tmpl.New("type").Parse(typeT))
+	tmpl = template.Must(tmpl.New("validation").Parse(validationT))
+	tmpl = template.Must(tmpl.New("view").Parse(viewT))
+
+	tmplV3 = newV3Template(tmpl)
+}
+
+// validatedElem returns the elements of t if t is an array whose elements have
+// validations, which the DSL expression of t does not describe. It returns nil
+// otherwise.
+func validatedElem(t design.DataType) *design.AttributeDefinition {
+	array, ok := t.(*design.Array)
+	if !ok || array.ElemType == nil || array.ElemType.Validation == nil {
+		return nil
+	}
+	return array.ElemType
+}
+
+// dataType returns the DSL expression of t. It returns an empty string for
+// anonymous objects, which are expected to be defined inline.
+func dataType(t design.DataType) string {
+	switch actual := t.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.BooleanKind:
+			return "Boolean"
+		case design.IntegerKind:
+			return "Integer"
+		case design.NumberKind:
+			return "Number"
+		case design.StringKind:
+			return "String"
+		case design.DateTimeKind:
+			return "DateTime"
+		case design.UUIDKind:
+			return "UUID"
+		case design.FileKind:
+			return "File"
+		default:
+			return "Any"
+		}
+	case *design.UserTypeDefinition:
+		return identifier(actual.TypeName)
+	case *design.MediaTypeDefinition:
+		if elem, ok := collectionElem(actual); ok {
+			return "CollectionOf(" + dataType(elem) + ")"
+		}
+		return identifier(actual.TypeName)
+	case *design.Array:
+		return "ArrayOf(" + dataType(actual.ElemType.Type) + ")"
+	case *design.Hash:
+		return "HashOf(" + dataType(actual.KeyType.Type) + ", " + dataType(actual.ElemType.Type) + ")"
+	case design.Object:
+		return ""
+	default:
+		return "Any"
+	}
+}
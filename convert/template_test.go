@@ -1,4 +1,4 @@
-package cmd
+package convert
 
 import (
 	"bytes"
@@ -158,6 +158,40 @@ func TestCredentialsTmpl(t *testing.T) {
 	}
 }
 
+func TestDefaultTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.AttributeDefinition{
+				DefaultValue: "available",
+			},
+			expected: `Default("available")`,
+		},
+		"with false": {
+			definition: design.AttributeDefinition{
+				DefaultValue: false,
+			},
+			expected: `Default(false)`,
+		},
+		"without definition": {
+			definition: design.AttributeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "default", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestDescriptionTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -240,6 +274,74 @@ func TestEmailTmpl(t *testing.T) {
 	}
 }
 
+func TestExampleTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.AttributeDefinition{
+				Example: []string{"cat", "dog"},
+			},
+			expected: `Example([]string{"cat", "dog"})`,
+		},
+		"with zero": {
+			definition: design.AttributeDefinition{
+				Example: 0,
+			},
+			expected: `Example(0)`,
+		},
+		"with object": {
+			definition: design.AttributeDefinition{
+				Example: map[string]interface{}{"name": "Kitty", "age": 2.5},
+			},
+			expected: `Example(map[string]interface{}{"age": 2.5, "name": "Kitty"})`,
+		},
+		"without definition": {
+			definition: design.AttributeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "example", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestFormatTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: dslengine.ValidationDefinition{
+				Format: "email",
+			},
+			expected: `Format("email")`,
+		},
+		"without definition": {
+			definition: dslengine.ValidationDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "format", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestFunctionTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -352,6 +454,34 @@ func TestMaxLengthTmpl(t *testing.T) {
 	}
 }
 
+func TestMaximumTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: dslengine.ValidationDefinition{
+				Maximum: &[]float64{10.5}[0],
+			},
+			expected: `Maximum(10.5)`,
+		},
+		"without definition": {
+			definition: dslengine.ValidationDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "maximum", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestMinLengthTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -380,6 +510,34 @@ func TestMinLengthTmpl(t *testing.T) {
 	}
 }
 
+func TestMinimumTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: dslengine.ValidationDefinition{
+				Minimum: &[]float64{1}[0],
+			},
+			expected: `Minimum(1)`,
+		},
+		"without definition": {
+			definition: dslengine.ValidationDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "minimum", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestNameTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -420,6 +578,34 @@ func TestNameTmpl(t *testing.T) {
 	}
 }
 
+func TestNoExampleTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.APIDefinition{
+				NoExamples: true,
+			},
+			expected: `NoExample()`,
+		},
+		"without definition": {
+			definition: design.APIDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "noExample", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestPackageTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -448,6 +634,92 @@ func TestPackageTmpl(t *testing.T) {
 	}
 }
 
+func TestParentTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.ResourceDefinition{
+				ParentName: "pets",
+			},
+			expected: `Parent("pets")`,
+		},
+		"without definition": {
+			definition: design.ResourceDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "parent", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestPatternTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: dslengine.ValidationDefinition{
+				Pattern: "^[a-z]+$",
+			},
+			expected: `Pattern("^[a-z]+$")`,
+		},
+		"without definition": {
+			definition: dslengine.ValidationDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "pattern", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestReferenceTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.AttributeDefinition{
+				Reference: &design.UserTypeDefinition{
+					TypeName: "pet",
+				},
+			},
+			expected: `Reference(Pet)`,
+		},
+		"without definition": {
+			definition: design.AttributeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "reference", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestStatusTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -637,39 +909,41 @@ func TestVersionTmpl(t *testing.T) {
 }
 
 // Components have multiple values.
-func TestExposeTmpl(t *testing.T) {
+func TestEnumTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
 		expected   string
 	}{
 		"with single definition": {
-			definition: design.CORSDefinition{
-				Exposed: []string{
-					"Authorization",
+			definition: dslengine.ValidationDefinition{
+				Values: []interface{}{
+					"foo",
 				},
 			},
-			expected: `Expose("Authorization")`,
+			expected: `Enum("foo")`,
 		},
 		"with multi definition": {
-			definition: design.CORSDefinition{
-				Exposed: []string{
-					"Authorization",
-					"Link",
+			definition: dslengine.ValidationDefinition{
+				Values: []interface{}{
+					1,
+					2.5,
+					true,
 				},
 			},
-			expected: `Expose(
-"Authorization",
-"Link",
+			expected: `Enum(
+1,
+2.5,
+true,
 )`,
 		},
 		"without definition": {
-			definition: design.CORSDefinition{},
+			definition: dslengine.ValidationDefinition{},
 			expected:   ``,
 		},
 	}
 	for k, tc := range cases {
 		buf := new(bytes.Buffer)
-		if err := tmpl.ExecuteTemplate(buf, "expose", tc.definition); err != nil {
+		if err := tmpl.ExecuteTemplate(buf, "enum", tc.definition); err != nil {
 			t.Fatalf("Execute returned %s", err)
 		}
 		actual := buf.String()
@@ -679,29 +953,29 @@ func TestExposeTmpl(t *testing.T) {
 	}
 }
 
-func TestMethodsTmpl(t *testing.T) {
+func TestExposeTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
 		expected   string
 	}{
 		"with single definition": {
 			definition: design.CORSDefinition{
-				Methods: []string{
-					"GET",
+				Exposed: []string{
+					"Authorization",
 				},
 			},
-			expected: `Methods("GET")`,
+			expected: `Expose("Authorization")`,
 		},
 		"with multi definition": {
 			definition: design.CORSDefinition{
-				Methods: []string{
-					"GET",
-					"POST",
+				Exposed: []string{
+					"Authorization",
+					"Link",
 				},
 			},
-			expected: `Methods(
-"GET",
-"POST",
+			expected: `Expose(
+"Authorization",
+"Link",
 )`,
 		},
 		"without definition": {
@@ -711,7 +985,131 @@ func TestMethodsTmpl(t *testing.T) {
 	}
 	for k, tc := range cases {
 		buf := new(bytes.Buffer)
-		if err := tmpl.ExecuteTemplate(buf, "methods", tc.definition); err != nil {
+		if err := tmpl.ExecuteTemplate(buf, "expose", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestMetadataTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with single definition": {
+			definition: design.ActionDefinition{
+				Metadata: dslengine.MetadataDefinition{
+					"swagger:generate": []string{"false"},
+				},
+			},
+			expected: `Metadata("swagger:generate", "false")`,
+		},
+		"with multi definition": {
+			definition: design.AttributeDefinition{
+				Metadata: dslengine.MetadataDefinition{
+					"struct:tag:json":   []string{"name", "omitempty"},
+					"swagger:read-only": []string{},
+				},
+			},
+			expected: `Metadata("struct:tag:json", "name", "omitempty")
+Metadata("swagger:read-only")`,
+		},
+		"without definition": {
+			definition: design.APIDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "metadata", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestMethodsTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with single definition": {
+			definition: design.CORSDefinition{
+				Methods: []string{
+					"GET",
+				},
+			},
+			expected: `Methods("GET")`,
+		},
+		"with multi definition": {
+			definition: design.CORSDefinition{
+				Methods: []string{
+					"GET",
+					"POST",
+				},
+			},
+			expected: `Methods(
+"GET",
+"POST",
+)`,
+		},
+		"without definition": {
+			definition: design.CORSDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "methods", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestRequiredTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with single definition": {
+			definition: dslengine.ValidationDefinition{
+				Required: []string{
+					"id",
+				},
+			},
+			expected: `Required("id")`,
+		},
+		"with multi definition": {
+			definition: dslengine.ValidationDefinition{
+				Required: []string{
+					"id",
+					"name",
+				},
+			},
+			expected: `Required(
+"id",
+"name",
+)`,
+		},
+		"without definition": {
+			definition: dslengine.ValidationDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "required", tc.definition); err != nil {
 			t.Fatalf("Execute returned %s", err)
 		}
 		actual := buf.String()
@@ -780,6 +1178,38 @@ func TestSchemeTmpl(t *testing.T) {
 }
 
 // Containers.
+func TestScopeTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with multi definition": {
+			definition: design.SecuritySchemeDefinition{
+				Scopes: map[string]string{
+					"write": "Write access",
+					"read":  "",
+				},
+			},
+			expected: `Scope("read")
+Scope("write", "Write access")`,
+		},
+		"without definition": {
+			definition: design.SecuritySchemeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "scope", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestActionTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -818,6 +1248,23 @@ URL("http://localhost/docs")
 Scheme("http")
 Routing(GET("/"))
 Payload(FooPayload)
+})`,
+		},
+		"with multipart payload": {
+			definition: design.ResourceDefinition{
+				Actions: map[string]*design.ActionDefinition{
+					"upload": &design.ActionDefinition{
+						Name: "upload",
+						Payload: &design.UserTypeDefinition{
+							TypeName: "UploadPayload",
+						},
+						PayloadMultipart: true,
+					},
+				},
+			},
+			expected: `Action("upload", func() {
+Payload(UploadPayload)
+MultipartForm()
 })`,
 		},
 		"without definition": {
@@ -935,6 +1382,14 @@ Credentials()
 })
 Consumes("application/json")
 Produces("application/json")
+})`,
+		},
+		"with no examples": {
+			definition: design.APIDefinition{
+				NoExamples: true,
+			},
+			expected: `var _ = API("", func() {
+NoExample()
 })`,
 		},
 		"without definition": {
@@ -955,6 +1410,164 @@ Produces("application/json")
 	}
 }
 
+func TestAttributeTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with multi definition": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"id": &design.AttributeDefinition{
+						Type: design.Integer,
+					},
+					"name": &design.AttributeDefinition{
+						Type:        design.String,
+						Description: "Name of pet",
+						Validation: &dslengine.ValidationDefinition{
+							MinLength: &[]int{1}[0],
+						},
+					},
+					"owner": &design.AttributeDefinition{
+						Type: &design.UserTypeDefinition{
+							TypeName: "Owner",
+						},
+					},
+					"tag": &design.AttributeDefinition{
+						Type: design.Object{
+							"name": &design.AttributeDefinition{
+								Type: design.String,
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{
+							Required: []string{"name"},
+						},
+					},
+				},
+			},
+			expected: `Attribute("id", Integer)
+Attribute("name", String, func() {
+Description("Name of pet")
+MinLength(1)
+})
+Attribute("owner", Owner)
+Attribute("tag", func() {
+Attribute("name", String)
+Required("name")
+})`,
+		},
+		"with single definition": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"id": &design.AttributeDefinition{
+						Type: design.Integer,
+					},
+				},
+			},
+			expected: `Attribute("id", Integer)`,
+		},
+		"with hash": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"counts": &design.AttributeDefinition{
+						Type: &design.Hash{
+							KeyType:  &design.AttributeDefinition{Type: design.String},
+							ElemType: &design.AttributeDefinition{Type: design.Integer},
+						},
+					},
+				},
+			},
+			expected: `Attribute("counts", HashOf(String, Integer))`,
+		},
+		"with array": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"tags": &design.AttributeDefinition{
+						Type: &design.Array{
+							ElemType: &design.AttributeDefinition{
+								Type: &design.Array{
+									ElemType: &design.AttributeDefinition{Type: design.String},
+								},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{
+							MaxLength: &[]int{3}[0],
+						},
+					},
+				},
+			},
+			expected: `Attribute("tags", ArrayOf(ArrayOf(String)), func() {
+MaxLength(3)
+})`,
+		},
+		"with values": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"status": &design.AttributeDefinition{
+						Type:         design.String,
+						DefaultValue: "available",
+						Example:      "sold",
+					},
+					"weight": &design.AttributeDefinition{
+						Type:    design.Number,
+						Example: 1.0,
+					},
+				},
+			},
+			expected: `Attribute("status", String, func() {
+Default("available")
+Example("sold")
+})
+Attribute("weight", Number, func() {
+Example(1.0)
+})`,
+		},
+		"with validated items": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"colors": &design.AttributeDefinition{
+						Type: &design.Array{
+							ElemType: &design.AttributeDefinition{
+								Type: design.String,
+								Validation: &dslengine.ValidationDefinition{
+									Values:    []interface{}{"black", "white"},
+									MaxLength: &[]int{5}[0],
+								},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{
+							MinLength: &[]int{1}[0],
+						},
+					},
+				},
+			},
+			expected: `Attribute("colors", ArrayOf(String, func() {
+Enum(
+"black",
+"white",
+)
+MaxLength(5)
+}), func() {
+MinLength(1)
+})`,
+		},
+		"without definition": {
+			definition: design.AttributeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "attribute", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestCONNECTTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -1306,6 +1919,57 @@ URL("http://localhost/api")
 	}
 }
 
+func TestFlowTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"access code": {
+			definition: design.SecuritySchemeDefinition{
+				Flow:             "accessCode",
+				AuthorizationURL: "https://example.com/auth",
+				TokenURL:         "https://example.com/token",
+			},
+			expected: `AccessCodeFlow("https://example.com/auth", "https://example.com/token")`,
+		},
+		"implicit": {
+			definition: design.SecuritySchemeDefinition{
+				Flow:             "implicit",
+				AuthorizationURL: "https://example.com/auth",
+			},
+			expected: `ImplicitFlow("https://example.com/auth")`,
+		},
+		"password": {
+			definition: design.SecuritySchemeDefinition{
+				Flow:     "password",
+				TokenURL: "https://example.com/token",
+			},
+			expected: `PasswordFlow("https://example.com/token")`,
+		},
+		"application": {
+			definition: design.SecuritySchemeDefinition{
+				Flow:     "application",
+				TokenURL: "https://example.com/token",
+			},
+			expected: `ApplicationFlow("https://example.com/token")`,
+		},
+		"without definition": {
+			definition: design.SecuritySchemeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "flow", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestGETTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -1378,31 +2042,72 @@ func TestHEADTmpl(t *testing.T) {
 	}
 }
 
-func TestLicenseTmpl(t *testing.T) {
+func TestHeadersTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
 		expected   string
 	}{
-		"with name/url definition": {
-			definition: design.APIDefinition{
-				License: &design.LicenseDefinition{
-					Name: "Name of license",
-					URL:  "http://localhost/license",
-				},
-			},
-			expected: `License(func() {
-Name("Name of license")
-URL("http://localhost/license")
-})`,
-		},
-		"with name definition": {
-			definition: design.APIDefinition{
-				License: &design.LicenseDefinition{
-					Name: "Name of license",
+		"with definition": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"X-Rate-Limit": &design.AttributeDefinition{
+						Type: design.Integer,
+					},
+					"X-Next": &design.AttributeDefinition{
+						Type:        design.String,
+						Description: "Description of header",
+					},
 				},
 			},
-			expected: `License(func() {
-Name("Name of license")
+			expected: `Headers(func() {
+Header("X-Next", String, func() {
+Description("Description of header")
+})
+Header("X-Rate-Limit", Integer)
+})`,
+		},
+		"without definition": {
+			definition: design.AttributeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "headers", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestLicenseTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with name/url definition": {
+			definition: design.APIDefinition{
+				License: &design.LicenseDefinition{
+					Name: "Name of license",
+					URL:  "http://localhost/license",
+				},
+			},
+			expected: `License(func() {
+Name("Name of license")
+URL("http://localhost/license")
+})`,
+		},
+		"with name definition": {
+			definition: design.APIDefinition{
+				License: &design.LicenseDefinition{
+					Name: "Name of license",
+				},
+			},
+			expected: `License(func() {
+Name("Name of license")
 })`,
 		},
 		"with url definition": {
@@ -1439,6 +2144,114 @@ URL("http://localhost/license")
 	}
 }
 
+func TestMediaTmpl(t *testing.T) {
+	petMedia := &design.MediaTypeDefinition{
+		UserTypeDefinition: &design.UserTypeDefinition{
+			TypeName: "PetMedia",
+		},
+	}
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with view": {
+			definition: design.ResponseDefinition{
+				Type:     petMedia,
+				ViewName: "tiny",
+			},
+			expected: `Media(PetMedia, "tiny")`,
+		},
+		"without view": {
+			definition: design.ResponseDefinition{
+				Type: petMedia,
+			},
+			expected: `Media(PetMedia)`,
+		},
+		"without definition": {
+			definition: design.ResponseDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "media", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestMediaTypeTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.APIDefinition{
+				MediaTypes: map[string]*design.MediaTypeDefinition{
+					"application/vnd.pet+json": &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id": &design.AttributeDefinition{
+										Type: design.Integer,
+									},
+								},
+								Validation: &dslengine.ValidationDefinition{
+									Required: []string{"id"},
+								},
+								Example: map[string]interface{}{"id": 1},
+							},
+							TypeName: "PetMedia",
+						},
+						Identifier:  "application/vnd.pet+json",
+						ContentType: "application/json",
+						Views: map[string]*design.ViewDefinition{
+							"default": &design.ViewDefinition{
+								AttributeDefinition: &design.AttributeDefinition{
+									Type: design.Object{
+										"id": &design.AttributeDefinition{},
+									},
+								},
+								Name: "default",
+							},
+						},
+					},
+				},
+			},
+			expected: `var PetMedia = MediaType("application/vnd.pet+json", func() {
+TypeName("PetMedia")
+ContentType("application/json")
+Attributes(func() {
+Attribute("id", Integer)
+Required("id")
+})
+View("default", func() {
+Attribute("id")
+})
+Example(map[string]interface{}{"id": 1})
+})`,
+		},
+		"without definition": {
+			definition: design.APIDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "mediaType", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestOPTIONSTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -1577,6 +2390,53 @@ func TestPATCHTmpl(t *testing.T) {
 	}
 }
 
+func TestParamsTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.AttributeDefinition{
+				Type: design.Object{
+					"limit": &design.AttributeDefinition{
+						Type: design.Integer,
+						Validation: &dslengine.ValidationDefinition{
+							Maximum: &[]float64{100}[0],
+						},
+					},
+					"id": &design.AttributeDefinition{
+						Type: design.String,
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{
+					Required: []string{"limit"},
+				},
+			},
+			expected: `Params(func() {
+Param("id", String)
+Param("limit", Integer, func() {
+Maximum(100)
+})
+Required("limit")
+})`,
+		},
+		"without definition": {
+			definition: design.AttributeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "params", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestPayloadTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -1590,6 +2450,25 @@ func TestPayloadTmpl(t *testing.T) {
 			},
 			expected: `Payload(FooPayload)`,
 		},
+		"with optional definition": {
+			definition: design.ActionDefinition{
+				Payload: &design.UserTypeDefinition{
+					TypeName: "FooPayload",
+				},
+				PayloadOptional: true,
+			},
+			expected: `OptionalPayload(FooPayload)`,
+		},
+		"with primitive definition": {
+			definition: design.ActionDefinition{
+				Payload: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.String,
+					},
+				},
+			},
+			expected: `Payload(String)`,
+		},
 		"without definition": {
 			definition: design.ActionDefinition{},
 			expected:   ``,
@@ -1812,6 +2691,81 @@ Package("encoding/json")
 	}
 }
 
+func TestResourceTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with multi definition": {
+			definition: design.APIDefinition{
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": &design.ResourceDefinition{
+						Name:                "foo",
+						Description:         "Description of resource",
+						BasePath:            "/foo",
+						CanonicalActionName: "show",
+						ParentName:          "root",
+						Actions: map[string]*design.ActionDefinition{
+							"show": &design.ActionDefinition{
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									&design.RouteDefinition{
+										Verb: "GET",
+										Path: "/:id",
+									},
+								},
+							},
+						},
+					},
+					"bar": &design.ResourceDefinition{
+						Name: "bar",
+						Schemes: []string{
+							"https",
+						},
+					},
+				},
+			},
+			expected: `var _ = Resource("bar", func() {
+Scheme("https")
+})
+var _ = Resource("foo", func() {
+Description("Description of resource")
+BasePath("/foo")
+CanonicalActionName("show")
+Parent("root")
+Action("show", func() {
+Routing(GET("/:id"))
+})
+})`,
+		},
+		"with single definition": {
+			definition: design.APIDefinition{
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": &design.ResourceDefinition{
+						Name: "foo",
+					},
+				},
+			},
+			expected: `var _ = Resource("foo", func() {
+})`,
+		},
+		"without definition": {
+			definition: design.APIDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "resource", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestResponseTmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -1841,6 +2795,86 @@ Response(FooMedia)`,
 			},
 			expected: `Response(FooMedia)`,
 		},
+		"with custom definition": {
+			definition: design.ActionDefinition{
+				Responses: map[string]*design.ResponseDefinition{
+					"Status299": &design.ResponseDefinition{
+						Name:        "Status299",
+						Status:      299,
+						Description: "Description of response",
+					},
+				},
+			},
+			expected: `Response("Status299", func() {
+Status(299)
+Description("Description of response")
+})`,
+		},
+		"with standard definition": {
+			definition: design.ActionDefinition{
+				Responses: map[string]*design.ResponseDefinition{
+					"OK": &design.ResponseDefinition{
+						Name:     "OK",
+						Status:   200,
+						Standard: true,
+						Type: &design.MediaTypeDefinition{
+							UserTypeDefinition: &design.UserTypeDefinition{
+								TypeName: "PetMedia",
+							},
+						},
+					},
+				},
+			},
+			expected: `Response(OK, PetMedia)`,
+		},
+		"with collection": {
+			definition: design.ActionDefinition{
+				Responses: map[string]*design.ResponseDefinition{
+					"OK": &design.ResponseDefinition{
+						Name:     "OK",
+						Status:   200,
+						Standard: true,
+						Type: &design.MediaTypeDefinition{
+							UserTypeDefinition: &design.UserTypeDefinition{
+								AttributeDefinition: &design.AttributeDefinition{
+									Type: &design.Array{
+										ElemType: &design.AttributeDefinition{
+											Type: &design.MediaTypeDefinition{
+												UserTypeDefinition: &design.UserTypeDefinition{
+													TypeName: "PetMedia",
+												},
+											},
+										},
+									},
+								},
+								TypeName: "PetMediaCollection",
+							},
+						},
+					},
+				},
+			},
+			expected: `Response(OK, CollectionOf(PetMedia))`,
+		},
+		"with view": {
+			definition: design.ActionDefinition{
+				Responses: map[string]*design.ResponseDefinition{
+					"OK": &design.ResponseDefinition{
+						Name:     "OK",
+						Status:   200,
+						Standard: true,
+						Type: &design.MediaTypeDefinition{
+							UserTypeDefinition: &design.UserTypeDefinition{
+								TypeName: "PetMedia",
+							},
+						},
+						ViewName: "tiny",
+					},
+				},
+			},
+			expected: `Response(OK, func() {
+Media(PetMedia, "tiny")
+})`,
+		},
 		"without definition": {
 			definition: design.ActionDefinition{},
 			expected:   ``,
@@ -1944,6 +2978,120 @@ TRACE("/"),
 	}
 }
 
+func TestSecurityTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with scopes": {
+			definition: design.ActionDefinition{
+				Security: &design.SecurityDefinition{
+					Scheme: &design.SecuritySchemeDefinition{
+						SchemeName: "oauth",
+					},
+					Scopes: []string{"read", "write"},
+				},
+			},
+			expected: `Security("oauth", func() {
+Scope("read")
+Scope("write")
+})`,
+		},
+		"without scopes": {
+			definition: design.ActionDefinition{
+				Security: &design.SecurityDefinition{
+					Scheme: &design.SecuritySchemeDefinition{
+						SchemeName: "basic",
+					},
+				},
+			},
+			expected: `Security("basic")`,
+		},
+		"no security": {
+			definition: design.ActionDefinition{
+				Security: &design.SecurityDefinition{
+					Scheme: &design.SecuritySchemeDefinition{
+						Kind: design.NoSecurityKind,
+					},
+				},
+			},
+			expected: `NoSecurity()`,
+		},
+		"without definition": {
+			definition: design.ActionDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "security", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestSecuritySchemeTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with multi definition": {
+			definition: design.APIDefinition{
+				SecuritySchemes: []*design.SecuritySchemeDefinition{
+					&design.SecuritySchemeDefinition{
+						SchemeName:  "basic",
+						Type:        "basic",
+						Description: "Description of scheme",
+					},
+					&design.SecuritySchemeDefinition{
+						SchemeName: "key",
+						Type:       "apiKey",
+						In:         "header",
+						Name:       "X-API-Key",
+					},
+					&design.SecuritySchemeDefinition{
+						SchemeName: "oauth",
+						Type:       "oauth2",
+						Flow:       "password",
+						TokenURL:   "https://example.com/token",
+						Scopes: map[string]string{
+							"read": "Read access",
+						},
+					},
+				},
+			},
+			expected: `var _ = BasicAuthSecurity("basic", func() {
+Description("Description of scheme")
+})
+var _ = APIKeySecurity("key", func() {
+Header("X-API-Key")
+})
+var _ = OAuth2Security("oauth", func() {
+PasswordFlow("https://example.com/token")
+Scope("read", "Read access")
+})`,
+		},
+		"without definition": {
+			definition: design.APIDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "securityScheme", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
 func TestTRACETmpl(t *testing.T) {
 	cases := map[string]struct {
 		definition interface{}
@@ -2010,6 +3158,54 @@ var FooPayload = Type("FooPayload", func() {
 				},
 			},
 			expected: `var FooPayload = Type("FooPayload", func() {
+})`,
+		},
+		"with attributes": {
+			definition: design.APIDefinition{
+				Types: map[string]*design.UserTypeDefinition{
+					"pet": &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Description: "Description of type",
+							Type: design.Object{
+								"id": &design.AttributeDefinition{
+									Type: design.Integer,
+								},
+							},
+							Validation: &dslengine.ValidationDefinition{
+								Required: []string{"id"},
+							},
+						},
+						TypeName: "pet",
+					},
+				},
+			},
+			expected: `var Pet = Type("pet", func() {
+Description("Description of type")
+Attribute("id", Integer)
+Required("id")
+})`,
+		},
+		"with reference": {
+			definition: design.APIDefinition{
+				Types: map[string]*design.UserTypeDefinition{
+					"Dog": &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"name": &design.AttributeDefinition{
+									Type: design.String,
+								},
+							},
+							Reference: &design.UserTypeDefinition{
+								TypeName: "Pet",
+							},
+						},
+						TypeName: "Dog",
+					},
+				},
+			},
+			expected: `var Dog = Type("Dog", func() {
+Reference(Pet)
+Attribute("name", String)
 })`,
 		},
 		"without definition": {
@@ -2028,3 +3224,98 @@ var FooPayload = Type("FooPayload", func() {
 		}
 	}
 }
+
+func TestValidationTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: dslengine.ValidationDefinition{
+				Values:    []interface{}{"foo"},
+				Format:    "email",
+				Pattern:   "^.+@.+$",
+				Minimum:   &[]float64{1}[0],
+				Maximum:   &[]float64{10}[0],
+				MinLength: &[]int{1}[0],
+				MaxLength: &[]int{5}[0],
+				Required:  []string{"id"},
+			},
+			expected: `Enum("foo")
+Format("email")
+Pattern("^.+@.+$")
+Minimum(1)
+Maximum(10)
+MinLength(1)
+MaxLength(5)
+Required("id")
+`,
+		},
+		"without definition": {
+			definition: dslengine.ValidationDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "validation", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestViewTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with multi definition": {
+			definition: design.MediaTypeDefinition{
+				Views: map[string]*design.ViewDefinition{
+					"default": &design.ViewDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"id":   &design.AttributeDefinition{},
+								"name": &design.AttributeDefinition{},
+							},
+						},
+						Name: "default",
+					},
+					"tiny": &design.ViewDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"id": &design.AttributeDefinition{},
+							},
+						},
+						Name: "tiny",
+					},
+				},
+			},
+			expected: `View("default", func() {
+Attribute("id")
+Attribute("name")
+})
+View("tiny", func() {
+Attribute("id")
+})`,
+		},
+		"without definition": {
+			definition: design.MediaTypeDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmpl.ExecuteTemplate(buf, "view", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package convert
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// Templates for goa v3 DSL. The templates that are not defined here are shared
+// with goa v1 because both DSLs have the same syntax for them.
+const (
+	v3GoHeaderT = `
+package design
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+`
+
+	// Containers.
+	v3AttributeArgsT = `{{$type := dataType .Type}}{{$format := impliedFormat .}}{{if $type}}, {{with validatedElem .Type}}{{template "arrayOf" .}}{{else}}{{$type}}{{end}}{{end}}{{if (or .Description .Validation .Metadata $format (literal .DefaultValue) (literal .Example) (not $type))}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if (not $type)}}{{template "attribute" .}}
+{{end}}{{if $format}}Format({{$format}})
+{{end}}{{with .Validation}}{{template "validation" .}}{{end}}{{if (literal .DefaultValue)}}{{template "default" .}}
+{{end}}{{if (literal .Example)}}{{template "example" .}}
+{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}{{end}}` // This template expects AttributeDefinition.
+	v3ActionT = `{{if .Actions}}{{$actions := .Actions}}{{$keys := keys .Actions}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $actions .}}Method({{printf "%q" .Name}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if .Docs}}{{template "docs" .}}
+{{end}}{{if .Security}}{{template "security" .}}
+{{end}}{{if (or .Payload .Params .Headers)}}{{template "payload" .}}
+{{end}}{{if .Responses}}{{template "result" .}}{{end}}{{if .Routes}}{{template "http" .}}
+{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}){{end}}{{end}}{{end}}`
+	v3APIT = `{{if .}}var _ = API({{if .Name}}{{printf "%q" .Name}}{{else}}""{{end}}, func() {
+{{if .Title}}{{template "title" .}}
+{{end}}{{if .Description}}{{template "description" .}}
+{{end}}{{if .Version}}{{template "version" .}}
+{{end}}{{if .TermsOfService}}{{template "termsOfService" .}}
+{{end}}{{if .Contact}}{{template "contact" .}}
+{{end}}{{if .License}}{{template "license" .}}
+{{end}}{{if .Docs}}{{template "docs" .}}
+{{end}}{{if .Host}}{{template "server" .}}
+{{end}}{{if .Security}}{{if .Security.Scheme.SchemeName}}{{template "security" .}}
+{{end}}{{end}}{{if (or .BasePath .Params)}}HTTP(func() {
+{{if .BasePath}}Path({{printf "%q" .BasePath}})
+{{end}}{{if .Params}}{{template "params" .Params}}
+{{end}}})
+{{end}}{{if .NoExamples}}{{template "noExample" .}}
+{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}){{end}}`
+	v3FlowT = `{{if (eq .Flow "accessCode")}}AuthorizationCodeFlow({{printf "%q" .AuthorizationURL}}, {{printf "%q" .TokenURL}}, ""){{else if (eq .Flow "implicit")}}ImplicitFlow({{printf "%q" .AuthorizationURL}}, ""){{else if (eq .Flow "password")}}PasswordFlow({{printf "%q" .TokenURL}}, ""){{else if (eq .Flow "application")}}ClientCredentialsFlow({{printf "%q" .TokenURL}}, ""){{end}}` // This template expects SecuritySchemeDefinition.
+	v3HTTPT = `{{if .Routes}}HTTP(func() {
+{{range .Routes}}{{.Verb}}({{printf "%q" (wildcards .Path)}})
+{{end}}{{with .QueryParams}}{{range $name := keys .Type.ToObject}}Param({{printf "%q" $name}})
+{{end}}{{end}}{{with .Headers}}{{range $name := keys .Type.ToObject}}Header({{printf "%q" $name}})
+{{end}}{{end}}{{if (or .Params .Headers)}}{{with .Payload}}{{if (not .TypeName)}}Body("body")
+{{end}}{{end}}{{end}}{{if .PayloadMultipart}}MultipartRequest()
+{{end}}{{with successResponse .Responses}}Response({{if .Standard}}Status{{.Name}}{{else}}{{.Status}}{{end}})
+{{end}}{{range errorResponses .Responses}}Response({{printf "%q" .Name}}, {{if .Standard}}Status{{.Name}}{{else}}{{.Status}}{{end}})
+{{end}}}){{end}}` // This template expects ActionDefinition.
+	v3MediaTypeT = `{{if .MediaTypes}}{{$mediaTypes := .MediaTypes}}{{$keys := keys .MediaTypes}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $mediaTypes .}}var {{goify .TypeName}} = ResultType({{printf "%q" .Identifier}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if .TypeName}}{{template "typeName" .}}
+{{end}}{{if .ContentType}}{{template "contentType" .}}
+{{end}}{{if .Type}}Attributes(func() {
+{{if .Type.ToObject}}{{template "attribute" .AttributeDefinition}}
+{{end}}{{with .Validation}}{{template "validation" .}}{{end}}})
+{{end}}{{if .Views}}{{template "view" .}}
+{{end}}{{with .AttributeDefinition}}{{if (literal .Example)}}{{template "example" .}}
+{{end}}{{end}}{{if .Metadata}}{{template "metadata" .}}
+{{end}}}){{end}}{{end}}{{end}}`
+	v3MetadataT = `{{if .Metadata}}{{range $i, $name := keys .Metadata}}{{if $i}}
+{{end}}Meta({{printf "%q" $name}}{{range index $.Metadata $name}}, {{printf "%q" .}}{{end}}){{end}}{{end}}` // This template expects APIDefinition or ActionDefinition or AttributeDefinition or MediaTypeDefinition.
+	v3PayloadT = `{{if (or .Params .Headers)}}Payload(func() {
+{{with .Payload}}{{if .TypeName}}Extend({{goify .TypeName}}){{else}}Attribute("body", {{dataType .Type}}){{end}}
+{{end}}{{with .Params}}{{template "attribute" .}}
+{{end}}{{with .Headers}}{{template "attribute" .}}
+{{end}}{{with .Params}}{{with .Validation}}{{template "required" .}}
+{{end}}{{end}}{{with .Headers}}{{with .Validation}}{{template "required" .}}
+{{end}}{{end}}{{if (not .PayloadOptional)}}{{with .Payload}}{{if (not .TypeName)}}Required("body")
+{{end}}{{end}}{{end}}}){{else}}{{with .Payload}}Payload({{if .TypeName}}{{goify .TypeName}}{{else}}{{dataType .Type}}{{end}}){{end}}{{end}}` // This template expects ActionDefinition.
+	v3ResourceT = `{{if .Resources}}{{$resources := .Resources}}{{$keys := keys .Resources}}{{range $keys}}{{if (not (eq (index $keys 0) .))}}
+{{end}}{{with index $resources .}}var _ = Service({{printf "%q" .Name}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if (or .BasePath .CanonicalActionName .ParentName)}}HTTP(func() {
+{{if .BasePath}}Path({{printf "%q" (wildcards .BasePath)}})
+{{end}}{{if .CanonicalActionName}}CanonicalMethod({{printf "%q" .CanonicalActionName}})
+{{end}}{{if .ParentName}}{{template "parent" .}}
+{{end}}})
+{{end}}{{if .Actions}}{{template "action" .}}
+{{end}}}){{end}}{{end}}{{end}}`
+	v3ResultT = `{{with successResponse .Responses}}{{if .Type}}Result({{dataType .Type}}{{if .ViewName}}, func() {
+View({{printf "%q" .ViewName}})
+}{{end}})
+{{end}}{{end}}{{range errorResponses .Responses}}Error({{printf "%q" .Name}}{{if .Type}}, {{dataType .Type}}{{end}})
+{{end}}` // This template expects ActionDefinition.
+	v3SecuritySchemeT = `{{if .SecuritySchemes}}{{range $i, $scheme := .SecuritySchemes}}{{if $i}}
+{{end}}var _ = {{if (eq .Type "basic")}}BasicAuthSecurity{{else if (eq .Type "apiKey")}}APIKeySecurity{{else}}OAuth2Security{{end}}({{printf "%q" .SchemeName}}{{if (or .Description .Flow .Scopes)}}, func() {
+{{if .Description}}{{template "description" .}}
+{{end}}{{if .Flow}}{{template "flow" .}}
+{{end}}{{if .Scopes}}{{template "scope" .}}
+{{end}}}{{end}}){{end}}{{end}}` // This template expects APIDefinition.
+	v3ServerT = `{{if .Host}}Server({{printf "%q" (serverName .Name)}}, func() {
+Host("default", func() {
+{{if .Schemes}}{{range .Schemes}}URI({{printf "%q" (print . "://" $.Host)}})
+{{end}}{{else}}URI({{printf "%q" (print "http://" .Host)}})
+{{end}}})
+}){{end}}` // This template expects APIDefinition.
+)
+
+var (
+	tmplV3 *template.Template
+
+	wildcardRegexp = regexp.MustCompile(`([:*])([^/]+)`)
+)
+
+// newV3Template returns the templates for goa v3 DSL based on base, the
+// templates for goa v1 DSL.
+func newV3Template(base *template.Template) *template.Template {
+	t := template.Must(base.Clone())
+
+	t = t.Funcs(template.FuncMap{
+		"dataType":       dataTypeV3,
+		"errorResponses": errorResponses,
+		"impliedFormat":  impliedFormat,
+		"serverName": func(name string) string {
+			if name == "" {
+				return "api"
+			}
+			return strings.ToLower(codegen.Goify(name, false))
+		},
+		"successResponse": successResponse,
+		"wildcards": func(path string) string {
+			return wildcardRegexp.ReplaceAllStringFunc(path, func(s string) string {
+				if s[0] == '*' {
+					return "{*" + s[1:] + "}"
+				}
+				return "{" + s[1:] + "}"
+			})
+		},
+	})
+
+	t = template.Must(replaceTemplate(t, "goHeader", v3GoHeaderT))
+
+	// Containers.
+	t = template.Must(replaceTemplate(t, "action", v3ActionT))
+	t = template.Must(replaceTemplate(t, "api", v3APIT))
+	t = template.Must(replaceTemplate(t, "attributeArgs", v3AttributeArgsT))
+	t = template.Must(replaceTemplate(t, "flow", v3FlowT))
+	t = template.Must(replaceTemplate(t, "http", v3HTTPT))
+	t = template.Must(replaceTemplate(t, "mediaType", v3MediaTypeT))
+	t = template.Must(replaceTemplate(t, "metadata", v3MetadataT))
+	t = template.Must(replaceTemplate(t, "payload", v3PayloadT))
+	t = template.Must(replaceTemplate(t, "resource", v3ResourceT))
+	t = template.Must(replaceTemplate(t, "result", v3ResultT))
+	t = template.Must(replaceTemplate(t, "securityScheme", v3SecuritySchemeT))
+	t = template.Must(replaceTemplate(t, "server", v3ServerT))
+	return t
+}
+
+// dataTypeV3 returns the DSL expression of t for goa v3. It returns an empty
+// string for anonymous objects, which are expected to be defined inline.
+func dataTypeV3(t design.DataType) string {
+	switch actual := t.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.BooleanKind:
+			return "Boolean"
+		case design.IntegerKind:
+			return "Int"
+		case design.NumberKind:
+			return "Float64"
+		case design.StringKind, design.DateTimeKind, design.UUIDKind:
+			return "String"
+		case design.FileKind:
+			return "Bytes"
+		default:
+			return "Any"
+		}
+	case *design.UserTypeDefinition:
+		return identifier(actual.TypeName)
+	case *design.MediaTypeDefinition:
+		if elem, ok := collectionElem(actual); ok {
+			return "CollectionOf(" + dataTypeV3(elem) + ")"
+		}
+		return identifier(actual.TypeName)
+	case *design.Array:
+		return "ArrayOf(" + dataTypeV3(actual.ElemType.Type) + ")"
+	case *design.Hash:
+		return "MapOf(" + dataTypeV3(actual.KeyType.Type) + ", " + dataTypeV3(actual.ElemType.Type) + ")"
+	case design.Object:
+		return ""
+	default:
+		return "Any"
+	}
+}
+
+// impliedFormat returns the format constant that validates the values of
+// attribute if its type is DateTime or UUID, which goa v3 describes as strings
+// with a format. It returns an empty string if the attribute validates
+// another format.
+func impliedFormat(attribute *design.AttributeDefinition) string {
+	if attribute.Validation != nil && attribute.Validation.Format != "" {
+		return ""
+	}
+	switch attribute.Type {
+	case design.DateTime:
+		return "FormatDateTime"
+	case design.UUID:
+		return "FormatUUID"
+	}
+	return ""
+}
+
+// successResponse returns the response with the lowest 2xx status among
+// responses, which goa v3 describes with Result. It returns nil if there is
+// no such response.
+func successResponse(responses map[string]*design.ResponseDefinition) *design.ResponseDefinition {
+	var success *design.ResponseDefinition
+	for _, r := range responses {
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		if success == nil || r.Status < success.Status {
+			success = r
+		}
+	}
+	return success
+}
+
+// errorResponses returns the responses whose status is not 2xx sorted by name.
+// goa v3 describes them with Error. The 2xx responses other than the success
+// response are not rendered.
+func errorResponses(responses map[string]*design.ResponseDefinition) []*design.ResponseDefinition {
+	var errors []*design.ResponseDefinition
+	for _, r := range responses {
+		if r.Status < 200 || r.Status >= 300 {
+			errors = append(errors, r)
+		}
+	}
+	sort.Slice(errors, func(i, j int) bool { return errors[i].Name < errors[j].Name })
+	return errors
+}
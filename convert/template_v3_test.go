@@ -0,0 +1,604 @@
+package convert
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+func TestV3GoHeaderTmpl(t *testing.T) {
+	cases := map[string]struct {
+		expected string
+	}{
+		"pattern 1": {
+			expected: `
+package design
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+`,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "goHeader", nil); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: got %v, expected %v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3ActionTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with single definition": {
+			definition: design.ResourceDefinition{
+				Actions: map[string]*design.ActionDefinition{
+					"show": &design.ActionDefinition{
+						Name:        "show",
+						Description: "Description of action",
+						Routes: []*design.RouteDefinition{
+							&design.RouteDefinition{
+								Verb: "GET",
+								Path: "/:id",
+							},
+						},
+						Payload: &design.UserTypeDefinition{
+							TypeName: "FooPayload",
+						},
+					},
+				},
+			},
+			expected: `Method("show", func() {
+Description("Description of action")
+Payload(FooPayload)
+HTTP(func() {
+GET("/{id}")
+})
+})`,
+		},
+		"without definition": {
+			definition: design.ResourceDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "action", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3APITmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.APIDefinition{
+				Name:    "Name of API",
+				Title:   "Title of API",
+				Version: "1.0.0",
+				Host:    "localhost:8080",
+				Schemes: []string{
+					"http",
+					"https",
+				},
+				BasePath: "/v1",
+			},
+			expected: `var _ = API("Name of API", func() {
+Title("Title of API")
+Version("1.0.0")
+Server("nameofapi", func() {
+Host("default", func() {
+URI("http://localhost:8080")
+URI("https://localhost:8080")
+})
+})
+HTTP(func() {
+Path("/v1")
+})
+})`,
+		},
+		"without definition": {
+			definition: design.APIDefinition{},
+			expected: `var _ = API("", func() {
+})`,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "api", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3HTTPTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with multi definition": {
+			definition: design.ActionDefinition{
+				Routes: []*design.RouteDefinition{
+					&design.RouteDefinition{
+						Verb: "GET",
+						Path: "/:id/*path",
+					},
+					&design.RouteDefinition{
+						Verb: "HEAD",
+						Path: "/:id",
+					},
+				},
+			},
+			expected: `HTTP(func() {
+GET("/{id}/{*path}")
+HEAD("/{id}")
+})`,
+		},
+		"with responses": {
+			definition: design.ActionDefinition{
+				Routes: []*design.RouteDefinition{
+					&design.RouteDefinition{
+						Verb: "GET",
+						Path: "/:id",
+					},
+				},
+				Responses: map[string]*design.ResponseDefinition{
+					"OK": &design.ResponseDefinition{
+						Name:     "OK",
+						Status:   200,
+						Standard: true,
+					},
+					"NotFound": &design.ResponseDefinition{
+						Name:     "NotFound",
+						Status:   404,
+						Standard: true,
+					},
+					"Status499": &design.ResponseDefinition{
+						Name:   "Status499",
+						Status: 499,
+					},
+					"Created": &design.ResponseDefinition{
+						Name:     "Created",
+						Status:   201,
+						Standard: true,
+					},
+				},
+			},
+			expected: `HTTP(func() {
+GET("/{id}")
+Response(StatusOK)
+Response("NotFound", StatusNotFound)
+Response("Status499", 499)
+})`,
+		},
+		"with params": {
+			definition: design.ActionDefinition{
+				Routes: []*design.RouteDefinition{
+					&design.RouteDefinition{
+						Verb: "PUT",
+						Path: "/:id",
+					},
+				},
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"id":    &design.AttributeDefinition{},
+						"limit": &design.AttributeDefinition{},
+					},
+				},
+				QueryParams: &design.AttributeDefinition{
+					Type: design.Object{
+						"limit": &design.AttributeDefinition{},
+					},
+				},
+				Headers: &design.AttributeDefinition{
+					Type: design.Object{
+						"X-Token": &design.AttributeDefinition{},
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.String,
+					},
+				},
+			},
+			expected: `HTTP(func() {
+PUT("/{id}")
+Param("limit")
+Header("X-Token")
+Body("body")
+})`,
+		},
+		"with multipart payload": {
+			definition: design.ActionDefinition{
+				Routes: []*design.RouteDefinition{
+					&design.RouteDefinition{
+						Verb: "POST",
+						Path: "/",
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					TypeName: "UploadPayload",
+				},
+				PayloadMultipart: true,
+			},
+			expected: `HTTP(func() {
+POST("/")
+MultipartRequest()
+})`,
+		},
+		"without definition": {
+			definition: design.ActionDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "http", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3MediaTypeTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.APIDefinition{
+				MediaTypes: map[string]*design.MediaTypeDefinition{
+					"application/vnd.pet+json": &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id": &design.AttributeDefinition{
+										Type: design.Integer,
+									},
+								},
+							},
+							TypeName: "PetMedia",
+						},
+						Identifier: "application/vnd.pet+json",
+					},
+				},
+			},
+			expected: `var PetMedia = ResultType("application/vnd.pet+json", func() {
+TypeName("PetMedia")
+Attributes(func() {
+Attribute("id", Int)
+})
+})`,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "mediaType", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3PayloadTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with params": {
+			definition: design.ActionDefinition{
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"id": &design.AttributeDefinition{
+							Type: design.String,
+						},
+					},
+				},
+				Headers: &design.AttributeDefinition{
+					Type: design.Object{
+						"X-Token": &design.AttributeDefinition{
+							Type: design.String,
+						},
+					},
+					Validation: &dslengine.ValidationDefinition{
+						Required: []string{"X-Token"},
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					TypeName: "FooPayload",
+				},
+			},
+			expected: `Payload(func() {
+Extend(FooPayload)
+Attribute("id", String)
+Attribute("X-Token", String)
+Required("X-Token")
+})`,
+		},
+		"with primitive payload": {
+			definition: design.ActionDefinition{
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"id": &design.AttributeDefinition{
+							Type: design.String,
+						},
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.String,
+					},
+				},
+			},
+			expected: `Payload(func() {
+Attribute("body", String)
+Attribute("id", String)
+Required("body")
+})`,
+		},
+		"without params": {
+			definition: design.ActionDefinition{
+				Payload: &design.UserTypeDefinition{
+					TypeName: "FooPayload",
+				},
+				PayloadOptional: true,
+			},
+			expected: `Payload(FooPayload)`,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "payload", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3ResourceTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.APIDefinition{
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": &design.ResourceDefinition{
+						Name:                "foo",
+						Description:         "Description of resource",
+						BasePath:            "/foo/:fooID",
+						CanonicalActionName: "show",
+					},
+				},
+			},
+			expected: `var _ = Service("foo", func() {
+Description("Description of resource")
+HTTP(func() {
+Path("/foo/{fooID}")
+CanonicalMethod("show")
+})
+})`,
+		},
+		"with parent": {
+			definition: design.APIDefinition{
+				Resources: map[string]*design.ResourceDefinition{
+					"toys": &design.ResourceDefinition{
+						Name:       "toys",
+						ParentName: "pets",
+					},
+				},
+			},
+			expected: `var _ = Service("toys", func() {
+HTTP(func() {
+Parent("pets")
+})
+})`,
+		},
+		"without definition": {
+			definition: design.APIDefinition{},
+			expected:   ``,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "resource", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3ResultTmpl(t *testing.T) {
+	petMedia := &design.MediaTypeDefinition{
+		UserTypeDefinition: &design.UserTypeDefinition{
+			TypeName: "PetMedia",
+		},
+	}
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with result and errors": {
+			definition: design.ActionDefinition{
+				Responses: map[string]*design.ResponseDefinition{
+					"Created": &design.ResponseDefinition{
+						Name:   "Created",
+						Status: 201,
+						Type:   petMedia,
+					},
+					"OK": &design.ResponseDefinition{
+						Name:   "OK",
+						Status: 200,
+						Type:   petMedia,
+					},
+					"BadRequest": &design.ResponseDefinition{
+						Name:   "BadRequest",
+						Status: 400,
+					},
+				},
+			},
+			expected: `Result(PetMedia)
+Error("BadRequest")
+`,
+		},
+		"without result": {
+			definition: design.ActionDefinition{
+				Responses: map[string]*design.ResponseDefinition{
+					"NoContent": &design.ResponseDefinition{
+						Name:   "NoContent",
+						Status: 204,
+					},
+					"NotFound": &design.ResponseDefinition{
+						Name:   "NotFound",
+						Status: 404,
+					},
+				},
+			},
+			expected: `Error("NotFound")
+`,
+		},
+		"with view": {
+			definition: design.ActionDefinition{
+				Responses: map[string]*design.ResponseDefinition{
+					"OK": &design.ResponseDefinition{
+						Name:     "OK",
+						Status:   200,
+						Type:     petMedia,
+						ViewName: "tiny",
+					},
+				},
+			},
+			expected: `Result(PetMedia, func() {
+View("tiny")
+})
+`,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "result", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestV3TypeTmpl(t *testing.T) {
+	cases := map[string]struct {
+		definition interface{}
+		expected   string
+	}{
+		"with definition": {
+			definition: design.APIDefinition{
+				Types: map[string]*design.UserTypeDefinition{
+					"Pet": &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"id": &design.AttributeDefinition{
+									Type:         design.Integer,
+									DefaultValue: 1,
+								},
+								"weight": &design.AttributeDefinition{
+									Type: design.Number,
+								},
+								"born": &design.AttributeDefinition{
+									Type: design.DateTime,
+								},
+								"uid": &design.AttributeDefinition{
+									Type:        design.UUID,
+									Description: "Unique ID",
+								},
+								"tags": &design.AttributeDefinition{
+									Type: &design.Array{
+										ElemType: &design.AttributeDefinition{Type: design.String},
+									},
+								},
+								"colors": &design.AttributeDefinition{
+									Type: &design.Array{
+										ElemType: &design.AttributeDefinition{
+											Type:       design.String,
+											Validation: &dslengine.ValidationDefinition{Values: []interface{}{"black", "white"}},
+										},
+									},
+								},
+								"labels": &design.AttributeDefinition{
+									Type: &design.Hash{
+										KeyType:  &design.AttributeDefinition{Type: design.String},
+										ElemType: &design.AttributeDefinition{Type: design.String},
+									},
+								},
+							},
+						},
+						TypeName: "Pet",
+					},
+				},
+			},
+			expected: `var Pet = Type("Pet", func() {
+Attribute("born", String, func() {
+Format(FormatDateTime)
+})
+Attribute("colors", ArrayOf(String, func() {
+Enum(
+"black",
+"white",
+)
+}))
+Attribute("id", Int, func() {
+Default(1)
+})
+Attribute("labels", MapOf(String, String))
+Attribute("tags", ArrayOf(String))
+Attribute("uid", String, func() {
+Description("Unique ID")
+Format(FormatUUID)
+})
+Attribute("weight", Float64)
+})`,
+		},
+	}
+	for k, tc := range cases {
+		buf := new(bytes.Buffer)
+		if err := tmplV3.ExecuteTemplate(buf, "type", tc.definition); err != nil {
+			t.Fatalf("Execute returned %s", err)
+		}
+		actual := buf.String()
+		if actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
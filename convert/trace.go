@@ -0,0 +1,231 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"go/scanner"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Markers that delimit the output of each template in a traced rendering.
+// The output of a template starts with traceEnter, the index of the call and
+// traceEnter again, and ends with traceLeave.
+const (
+	traceEnter = '\x00'
+	traceLeave = '\x01'
+)
+
+// excerptContext is the number of lines shown around a syntax error.
+const excerptContext = 2
+
+// templateCall is a call of a template during a traced rendering.
+type templateCall struct {
+	name string
+	data interface{}
+}
+
+// templateTracer records the template calls of a traced rendering.
+type templateTracer struct {
+	calls []templateCall
+}
+
+// enter records a call of the template named name with data and returns the
+// marker that starts its output.
+func (tr *templateTracer) enter(name string, data interface{}) string {
+	tr.calls = append(tr.calls, templateCall{name, data})
+	return string(traceEnter) + strconv.Itoa(len(tr.calls)-1) + string(traceEnter)
+}
+
+// leave returns the marker that ends the output of a template.
+func (tr *templateTracer) leave() string {
+	return string(traceLeave)
+}
+
+// trace returns a copy of t whose templates mark their output with the
+// markers of tr.
+func (tr *templateTracer) trace(t *template.Template) (*template.Template, error) {
+	traced, err := t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	funcs := template.FuncMap{"enterTemplate": tr.enter, "leaveTemplate": tr.leave}
+	traced.Funcs(funcs)
+	for _, tt := range t.Templates() {
+		if tt.Tree == nil || tt.Tree.Root == nil {
+			continue
+		}
+		// The trees are shared with t so they are copied before being
+		// modified.
+		tree := tt.Tree.Copy()
+		enter, err := template.New("").Funcs(funcs).Parse(fmt.Sprintf("{{enterTemplate %q .}}", tt.Name()))
+		if err != nil {
+			return nil, err
+		}
+		leave, err := template.New("").Funcs(funcs).Parse("{{leaveTemplate}}")
+		if err != nil {
+			return nil, err
+		}
+		nodes := append(enter.Tree.Root.Nodes, tree.Root.Nodes...)
+		tree.Root.Nodes = append(nodes, leave.Tree.Root.Nodes...)
+		if _, err := traced.AddParseTree(tt.Name(), tree); err != nil {
+			return nil, err
+		}
+	}
+	return traced, nil
+}
+
+// callsAt removes the markers from output and returns the template calls
+// whose output contains the given position, which is 1-based, from the
+// outermost to the innermost.
+func (tr *templateTracer) callsAt(output []byte, line, column int) []templateCall {
+	var stack, found []int
+	l, col := 1, 1
+	for i := 0; i < len(output); i++ {
+		switch b := output[i]; b {
+		case traceEnter:
+			j := bytes.IndexByte(output[i+1:], traceEnter)
+			index, _ := strconv.Atoi(string(output[i+1 : i+1+j]))
+			stack = append(stack, index)
+			i += j + 1
+		case traceLeave:
+			stack = stack[:len(stack)-1]
+		default:
+			if found == nil && l == line && (col >= column || b == '\n') {
+				found = append([]int{}, stack...)
+			}
+			if b == '\n' {
+				l, col = l+1, 1
+			} else {
+				col++
+			}
+		}
+	}
+	calls := make([]templateCall, len(found))
+	for i, index := range found {
+		calls[i] = tr.calls[index]
+	}
+	return calls
+}
+
+// SyntaxError is an error in the code generated for an API, which is caused
+// by a template.
+type SyntaxError struct {
+	// Filename is the name of the generated file in the package layout.
+	Filename string
+	// Line and Column are the position of the error in the generated code.
+	Line, Column int
+	// Message describes the error.
+	Message string
+	// Template is the name of the innermost template that generated the
+	// erroneous code.
+	Template string
+	// Pointer is the JSON pointer of the swagger node that the erroneous
+	// code is generated from, if known.
+	Pointer string
+	// Excerpt holds the generated lines around the error.
+	Excerpt string
+	// File is the temporary file where the unformatted code is written.
+	File string
+}
+
+func (e *SyntaxError) Error() string {
+	position := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.Filename != "" {
+		position = e.Filename + ":" + position
+	}
+	message := fmt.Sprintf("generated code does not compile: %s: %s", position, e.Message)
+	if e.Template != "" {
+		message += fmt.Sprintf(" (template %q)", e.Template)
+	}
+	message += "\n" + e.Excerpt
+	if e.File != "" {
+		message += "\nthe unformatted code is written to " + e.File
+	}
+	return message
+}
+
+// syntaxError returns the error that reports the syntax error err found in
+// source, the output of the template named name of t with data. The error
+// refers to the innermost template that produced the erroneous code and to
+// the swagger node found in origins that the data of the template, or of an
+// enclosing template, is converted from. source is written to a temporary
+// file for inspection.
+func syntaxError(t *template.Template, name string, data interface{}, origins map[interface{}]string, source []byte, err error) error {
+	list, ok := err.(scanner.ErrorList)
+	if !ok || len(list) == 0 {
+		return err
+	}
+	pos := list[0].Pos
+	e := &SyntaxError{
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Message: list[0].Msg,
+		Excerpt: excerpt(source, pos.Line, pos.Column),
+	}
+	tr := &templateTracer{}
+	if traced, err := tr.trace(t); err == nil {
+		buf := new(bytes.Buffer)
+		if err := traced.ExecuteTemplate(buf, name, data); err == nil {
+			calls := tr.callsAt(buf.Bytes(), pos.Line, pos.Column)
+			if len(calls) > 0 {
+				e.Template = calls[len(calls)-1].name
+			}
+			for i := len(calls) - 1; i >= 0 && e.Pointer == ""; i-- {
+				if data := calls[i].data; data != nil && reflect.TypeOf(data).Kind() == reflect.Ptr {
+					e.Pointer = origins[data]
+				}
+			}
+		}
+	}
+	if f, err := ioutil.TempFile("", "ago-*.go"); err == nil {
+		if _, err := f.Write(source); err == nil {
+			e.File = f.Name()
+		}
+		f.Close()
+	}
+	return e
+}
+
+// excerpt returns the lines of source around line with a caret under
+// column.
+func excerpt(source []byte, line, column int) string {
+	lines := strings.Split(string(source), "\n")
+	first, last := line-excerptContext, line+excerptContext
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	width := len(strconv.Itoa(last))
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		mark := " "
+		if n == line {
+			mark = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d | %s\n", mark, width, n, lines[n-1])
+		if n == line {
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", indent(lines[n-1], column))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// indent returns the blanks that align a caret with column of line. Tabs are
+// kept so that the caret is aligned whatever their width.
+func indent(line string, column int) string {
+	if column-1 < len(line) {
+		line = line[:column-1]
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, line)
+}
@@ -0,0 +1,51 @@
+package convert
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestSyntaxError(t *testing.T) {
+	type value struct{ Expression string }
+	broken := &value{"1 )"}
+	tmpl := template.Must(template.New("file").Parse("package design\n\n{{range .}}{{template \"value\" .}}\n{{end}}"))
+	template.Must(tmpl.New("value").Parse("var _ = {{.Expression}}"))
+	data := []*value{{"1"}, broken, {"2"}}
+	origins := map[interface{}]string{broken: "/definitions/Broken"}
+
+	_, err := renderTemplate(tmpl, "file", data, origins)
+	if err == nil {
+		t.Fatalf("renderTemplate accepted invalid code")
+	}
+	e, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("got %T, expected *SyntaxError", err)
+	}
+	defer os.Remove(e.File)
+	if e.Pointer != "/definitions/Broken" || e.Template != "value" || e.Line != 4 {
+		t.Errorf("got %s at line %d of template %q, expected /definitions/Broken at line 4 of template \"value\"", e.Pointer, e.Line, e.Template)
+	}
+	for _, expected := range []string{
+		`(template "value")`,
+		"> 4 | var _ = 1 )\n",
+		"  3 | var _ = 1\n",
+		"written to " + e.File,
+	} {
+		if !strings.Contains(e.Error(), expected) {
+			t.Errorf("message does not contain %q:\n%s", expected, e.Error())
+		}
+	}
+	if _, err := os.Stat(e.File); err != nil {
+		t.Errorf("unformatted code is not written: %s", err)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	source := []byte("a\nb\n\tc d\ne\nf\ng")
+	expected := "  1 | a\n  2 | b\n> 3 | \tc d\n    | \t  ^\n  4 | e\n  5 | f"
+	if actual := excerpt(source, 3, 4); actual != expected {
+		t.Errorf("\ngot:\n%v\nexpected:\n%v", actual, expected)
+	}
+}
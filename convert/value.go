@@ -0,0 +1,291 @@
+package convert
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// applyValues sets the default value and the example of attribute from the
+// node being converted. They are read from the node because the decoded
+// schemas lose the keys of object values named additionalProperties, and the
+// decoded parameters and headers have no examples. Values that do not match
+// the type of attribute are warned about and ignored.
+func (c *converter) applyValues(attribute *design.AttributeDefinition) {
+	object, _ := c.node().(map[string]interface{})
+	if v, ok := object["default"]; ok {
+		attribute.DefaultValue = c.value(attribute, "default", v)
+	}
+	for _, key := range []string{"example", extensionExample} {
+		if v, ok := object[key]; ok {
+			attribute.Example = c.value(attribute, key, v)
+			break
+		}
+	}
+}
+
+// value returns v, the value at key of the node being converted, as a value
+// of the type of attribute, or nil if v does not match the type.
+func (c *converter) value(attribute *design.AttributeDefinition, key string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	value, ok := valueToType(v, attribute.Type)
+	if !ok {
+		defer c.at(key)()
+		c.warnf("%s does not match the type %s and is ignored", key, attribute.Type.Name())
+		return nil
+	}
+	return value
+}
+
+// noExamplesExtension returns true if the root disables the generation of
+// examples with the x-no-examples vendor extension.
+func (c *converter) noExamplesExtension() bool {
+	v := c.extension(extensionNoExamples)
+	if v == nil {
+		return false
+	}
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	defer c.at(extensionNoExamples)()
+	c.warnf("%s must be a boolean and is ignored", extensionNoExamples)
+	return false
+}
+
+// valueToType converts v, a value decoded from the document, to a value of t
+// that goa accepts, so that literal renders it with the Go type of t:
+// integers become ints and arrays and hashes of primitives become slices and
+// maps of their Go types. It returns false if v does not match t. Values of
+// user types that are not converted yet and of Any are kept.
+func valueToType(v interface{}, t design.DataType) (interface{}, bool) {
+	switch actual := t.(type) {
+	case design.Primitive:
+		return primitiveValue(v, actual)
+	case *design.Array:
+		values, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		elem := actual.ElemType.Type
+		converted := make([]interface{}, len(values))
+		for i, value := range values {
+			if converted[i], ok = valueToType(value, elem); !ok {
+				return nil, false
+			}
+		}
+		return typedSlice(converted, elem), true
+	case *design.Hash:
+		values, ok := v.(map[string]interface{})
+		if !ok || actual.KeyType.Type != design.String {
+			return nil, false
+		}
+		elem := actual.ElemType.Type
+		converted := make(map[string]interface{}, len(values))
+		for k, value := range values {
+			if converted[k], ok = valueToType(value, elem); !ok {
+				return nil, false
+			}
+		}
+		return typedMap(converted, elem), true
+	case design.Object:
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		converted := make(map[string]interface{}, len(values))
+		for k, value := range values {
+			converted[k] = value
+			if attribute, ok := actual[k]; ok && value != nil {
+				if converted[k], ok = valueToType(value, attribute.Type); !ok {
+					return nil, false
+				}
+			}
+		}
+		return converted, true
+	case *design.UserTypeDefinition:
+		if actual.AttributeDefinition == nil || actual.Type == nil {
+			return v, true
+		}
+		return valueToType(v, actual.Type)
+	case *design.MediaTypeDefinition:
+		if actual.UserTypeDefinition == nil {
+			return v, true
+		}
+		return valueToType(v, actual.UserTypeDefinition)
+	}
+	return v, true
+}
+
+// primitiveValue converts v to a value of t.
+func primitiveValue(v interface{}, t design.Primitive) (interface{}, bool) {
+	switch t.Kind() {
+	case design.BooleanKind:
+		b, ok := v.(bool)
+		return b, ok
+	case design.IntegerKind:
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+			return nil, false
+		}
+		return int(f), true
+	case design.NumberKind:
+		f, ok := v.(float64)
+		return f, ok
+	case design.StringKind, design.DateTimeKind, design.UUIDKind, design.FileKind:
+		s, ok := v.(string)
+		return s, ok
+	}
+	return v, true
+}
+
+// typedSlice returns values, converted by valueToType to values of elem, as a
+// slice of the Go type of elem if elem is a primitive.
+func typedSlice(values []interface{}, elem design.DataType) interface{} {
+	switch goKind(elem) {
+	case design.BooleanKind:
+		typed := make([]bool, len(values))
+		for i, v := range values {
+			typed[i] = v.(bool)
+		}
+		return typed
+	case design.IntegerKind:
+		typed := make([]int, len(values))
+		for i, v := range values {
+			typed[i] = v.(int)
+		}
+		return typed
+	case design.NumberKind:
+		typed := make([]float64, len(values))
+		for i, v := range values {
+			typed[i] = v.(float64)
+		}
+		return typed
+	case design.StringKind:
+		typed := make([]string, len(values))
+		for i, v := range values {
+			typed[i] = v.(string)
+		}
+		return typed
+	}
+	return values
+}
+
+// typedMap returns values, converted by valueToType to values of elem, as a
+// map of the Go type of elem if elem is a primitive.
+func typedMap(values map[string]interface{}, elem design.DataType) interface{} {
+	switch goKind(elem) {
+	case design.BooleanKind:
+		typed := make(map[string]bool, len(values))
+		for k, v := range values {
+			typed[k] = v.(bool)
+		}
+		return typed
+	case design.IntegerKind:
+		typed := make(map[string]int, len(values))
+		for k, v := range values {
+			typed[k] = v.(int)
+		}
+		return typed
+	case design.NumberKind:
+		typed := make(map[string]float64, len(values))
+		for k, v := range values {
+			typed[k] = v.(float64)
+		}
+		return typed
+	case design.StringKind:
+		typed := make(map[string]string, len(values))
+		for k, v := range values {
+			typed[k] = v.(string)
+		}
+		return typed
+	}
+	return values
+}
+
+// goKind returns the kind of the Go type of the values of t if t is a
+// primitive other than Any, or 0. Strings, date-times, UUIDs and files are
+// all strings.
+func goKind(t design.DataType) design.Kind {
+	p, ok := t.(design.Primitive)
+	if !ok {
+		return 0
+	}
+	switch p.Kind() {
+	case design.DateTimeKind, design.UUIDKind, design.FileKind:
+		return design.StringKind
+	case design.AnyKind:
+		return 0
+	}
+	return p.Kind()
+}
+
+// literal returns the Go literal of v, a value converted by valueToType, or an
+// empty string if v is nil. Slices and maps are rendered with their Go types,
+// and the keys of maps are sorted.
+func literal(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return valueLiteral(reflect.ValueOf(v))
+}
+
+// valueLiteral returns the Go literal of v.
+func valueLiteral(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return "nil"
+		}
+		return valueLiteral(v.Elem())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		s := strconv.FormatFloat(v.Float(), 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Slice:
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = valueLiteral(v.Index(i))
+		}
+		return goTypeName(v.Type()) + "{" + strings.Join(elems, ", ") + "}"
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		entries := make([]string, len(keys))
+		for i, k := range keys {
+			entries[i] = strconv.Quote(k) + ": " + valueLiteral(v.MapIndex(reflect.ValueOf(k)))
+		}
+		return goTypeName(v.Type()) + "{" + strings.Join(entries, ", ") + "}"
+	}
+	return "nil"
+}
+
+// goTypeName returns the name of t in Go source, which reflect spells
+// differently for empty interfaces.
+func goTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Interface:
+		return "interface{}"
+	case reflect.Slice:
+		return "[]" + goTypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + goTypeName(t.Key()) + "]" + goTypeName(t.Elem())
+	}
+	return t.String()
+}
@@ -0,0 +1,144 @@
+package convert
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+)
+
+func TestValueToType(t *testing.T) {
+	cases := map[string]struct {
+		value    interface{}
+		dataType design.DataType
+		expected interface{}
+		ok       bool
+	}{
+		"integer":        {float64(3), design.Integer, 3, true},
+		"fraction":       {1.5, design.Integer, nil, false},
+		"number":         {float64(3), design.Number, float64(3), true},
+		"string":         {"2018-01-01T00:00:00Z", design.DateTime, "2018-01-01T00:00:00Z", true},
+		"wrong type":     {"3", design.Integer, nil, false},
+		"any":            {float64(3), design.Any, float64(3), true},
+		"array":          {[]interface{}{float64(1), float64(2)}, &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}}, []int{1, 2}, true},
+		"array of any":   {[]interface{}{"a", true}, &design.Array{ElemType: &design.AttributeDefinition{Type: design.Any}}, []interface{}{"a", true}, true},
+		"wrong elements": {[]interface{}{"a", true}, &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}, nil, false},
+		"hash": {
+			map[string]interface{}{"a": "x"},
+			&design.Hash{KeyType: &design.AttributeDefinition{Type: design.String}, ElemType: &design.AttributeDefinition{Type: design.String}},
+			map[string]string{"a": "x"},
+			true,
+		},
+		"object": {
+			map[string]interface{}{"id": float64(1), "extra": float64(2)},
+			design.Object{"id": &design.AttributeDefinition{Type: design.Integer}},
+			map[string]interface{}{"id": 1, "extra": float64(2)},
+			true,
+		},
+		"unconverted type": {
+			map[string]interface{}{"id": float64(1)},
+			&design.UserTypeDefinition{TypeName: "Pet"},
+			map[string]interface{}{"id": float64(1)},
+			true,
+		},
+	}
+	for k, tc := range cases {
+		actual, ok := valueToType(tc.value, tc.dataType)
+		if ok != tc.ok || !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: got %#v, %t, expected %#v, %t", k, actual, ok, tc.expected, tc.ok)
+		}
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	cases := map[string]struct {
+		value    interface{}
+		expected string
+	}{
+		"nil":      {nil, ``},
+		"boolean":  {false, `false`},
+		"integer":  {42, `42`},
+		"float":    {float64(2), `2.0`},
+		"exponent": {1e21, `1e+21`},
+		"string":   {"a \"quoted\" value", `"a \"quoted\" value"`},
+		"slice":    {[]string{"a", "b"}, `[]string{"a", "b"}`},
+		"map":      {map[string]int{"b": 2, "a": 1}, `map[string]int{"a": 1, "b": 2}`},
+		"nested": {
+			map[string]interface{}{"tags": []interface{}{"x", nil}, "owner": map[string]interface{}{"id": 1}},
+			`map[string]interface{}{"owner": map[string]interface{}{"id": 1}, "tags": []interface{}{"x", nil}}`,
+		},
+	}
+	for k, tc := range cases {
+		if actual := literal(tc.value); actual != tc.expected {
+			t.Errorf("%s: got %s, expected %s", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestConvertValues(t *testing.T) {
+	document := `swagger: "2.0"
+x-no-examples: true
+paths:
+  /pets:
+    get:
+      operationId: list
+      parameters:
+      - {name: limit, in: query, type: integer, default: 20, x-example: 5}
+      - {name: tags, in: query, type: array, items: {type: string}, default: [cat, dog]}
+      responses:
+        "200": {description: OK}
+definitions:
+  Pet:
+    type: object
+    example: {name: Kitty, age: 2}
+    properties:
+      name: {type: string, example: Kitty}
+      age: {type: integer, default: "one"}
+      weight: {type: number, example: 4}
+      labels: {type: object, additionalProperties: {type: string}, example: {color: black}}
+`
+	var warnings []string
+	api, err := Convert(strings.NewReader(document), Options{
+		Warn: func(d Diagnostic) {
+			warnings = append(warnings, d.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Convert returned %s", err)
+	}
+	if !api.NoExamples {
+		t.Errorf("NoExamples is not set")
+	}
+	pet := api.Types["Pet"]
+	if expected := map[string]interface{}{"name": "Kitty", "age": 2}; !reflect.DeepEqual(pet.Example, expected) {
+		t.Errorf("got example %#v, expected %#v", pet.Example, expected)
+	}
+	properties := pet.Type.ToObject()
+	params := api.Resources["pets"].Actions["list"].QueryParams.Type.ToObject()
+	cases := map[string]struct {
+		attribute    *design.AttributeDefinition
+		defaultValue interface{}
+		example      interface{}
+	}{
+		"name":   {properties["name"], nil, "Kitty"},
+		"age":    {properties["age"], nil, nil},
+		"weight": {properties["weight"], nil, float64(4)},
+		"labels": {properties["labels"], nil, map[string]string{"color": "black"}},
+		"limit":  {params["limit"], 20, 5},
+		"tags":   {params["tags"], []string{"cat", "dog"}, nil},
+	}
+	for k, tc := range cases {
+		if !reflect.DeepEqual(tc.attribute.DefaultValue, tc.defaultValue) || !reflect.DeepEqual(tc.attribute.Example, tc.example) {
+			t.Errorf("%s: got default %#v and example %#v, expected %#v and %#v", k, tc.attribute.DefaultValue, tc.attribute.Example, tc.defaultValue, tc.example)
+		}
+	}
+	expectedWarnings := []string{
+		"warning: /definitions/Pet/properties/age/default: default does not match the type integer and is ignored",
+	}
+	sort.Strings(warnings)
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("\ngot:\n%s\nexpected:\n%s", strings.Join(warnings, "\n"), strings.Join(expectedWarnings, "\n"))
+	}
+}
@@ -0,0 +1,244 @@
+package convert
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+// Difference is a difference between a swagger document and the swagger that
+// goa generates from its design. Pointer is the JSON pointer of the node of
+// the document that is missing or changed.
+type Difference struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func (d Difference) String() string {
+	return d.Pointer + ": " + d.Message
+}
+
+// Verify converts the document read from r like Convert, renders the design
+// and validates it, generates swagger from the design with goa and returns
+// the differences between the paths, the parameters and the responses of
+// both. The paths and the operations ignored by opts are not compared.
+func Verify(r io.Reader, opts Options) ([]Difference, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	c, swagger, api, err := convert(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := Render(api, ioutil.Discard, RenderOptions{}); err != nil {
+		return nil, err
+	}
+	regenerated, err := regenerate(api)
+	if err != nil {
+		return nil, err
+	}
+	return c.diffSwagger(swagger.Swagger, *regenerated)
+}
+
+// regenerate returns the swagger that goa generates from api. goa expects an
+// evaluated design, so api is validated by goa like the rendered DSL would be
+// when it is run, and the panics of the generator are returned as errors.
+func regenerate(api *design.APIDefinition) (swagger *genswagger.Swagger, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("goa cannot generate swagger from the design: %v", r)
+		}
+	}()
+	defer func(d *design.APIDefinition) { design.Design = d }(design.Design)
+	design.Design = api
+	addCollections(api)
+	if err := api.Validate(); err != nil {
+		return nil, fmt.Errorf("goa rejects the design: %s", err)
+	}
+	genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	return genswagger.New(api)
+}
+
+// addCollections adds the collections that the responses of api refer to to
+// the media types of api, as running CollectionOf does.
+func addCollections(api *design.APIDefinition) {
+	for _, resource := range api.Resources {
+		for _, action := range resource.Actions {
+			for _, response := range action.Responses {
+				mediaType, ok := response.Type.(*design.MediaTypeDefinition)
+				if !ok {
+					continue
+				}
+				if _, ok := collectionElem(mediaType); ok {
+					api.MediaTypes[design.CanonicalIdentifier(mediaType.Identifier)] = mediaType
+				}
+			}
+		}
+	}
+}
+
+// diffSwagger returns the differences between original and regenerated in
+// the order of the paths of original.
+func (c *converter) diffSwagger(original, regenerated genswagger.Swagger) ([]Difference, error) {
+	paths := make(map[string]*genswagger.Path)
+	for p, v := range regenerated.Paths {
+		if strings.HasPrefix(p, "x-") {
+			continue
+		}
+		path, err := decodePath(v)
+		if err != nil {
+			return nil, err
+		}
+		paths[fullPath(regenerated.BasePath, p)] = path
+	}
+	var keys []string
+	for p := range original.Paths {
+		if !strings.HasPrefix(p, "x-") && !matchAny(c.ignoredPaths, p) {
+			keys = append(keys, p)
+		}
+	}
+	sort.Strings(keys)
+	var differences []Difference
+	for _, p := range keys {
+		path, err := decodePath(original.Paths[p])
+		if err != nil {
+			return nil, &Error{Pointer: jsonPointer([]string{"paths", p}), Message: err.Error()}
+		}
+		var operations []verbOperation
+		for _, o := range pathOperations(path) {
+			if !c.isIgnoredOperation(o.operation) {
+				operations = append(operations, o)
+			}
+		}
+		if len(operations) == 0 {
+			continue
+		}
+		other, ok := paths[fullPath(original.BasePath, p)]
+		if !ok {
+			differences = append(differences, Difference{
+				Pointer: jsonPointer([]string{"paths", p}),
+				Message: fmt.Sprintf("path %q is missing", p),
+			})
+			continue
+		}
+		otherOperations := make(map[string]*genswagger.Operation)
+		for _, o := range pathOperations(other) {
+			otherOperations[o.verb] = o.operation
+		}
+		for _, o := range operations {
+			pointer := []string{"paths", p, strings.ToLower(o.verb)}
+			otherOperation, ok := otherOperations[o.verb]
+			if !ok {
+				differences = append(differences, Difference{
+					Pointer: jsonPointer(pointer),
+					Message: fmt.Sprintf("operation %s %s is missing", o.verb, p),
+				})
+				continue
+			}
+			differences = append(differences, diffParameters(pointer, path.Parameters, o.operation, other.Parameters, otherOperation)...)
+			differences = append(differences, diffResponses(pointer, o.operation, otherOperation)...)
+		}
+	}
+	return differences, nil
+}
+
+// fullPath returns the path p of the API whose base path is basePath.
+func fullPath(basePath, p string) string {
+	return path.Join("/", basePath, p)
+}
+
+// diffParameters returns the parameters of operation that are missing in
+// otherOperation or whose type is changed. pathParameters and
+// otherPathParameters are the parameters shared by the operations of the
+// paths.
+func diffParameters(pointer []string, pathParameters []*genswagger.Parameter, operation *genswagger.Operation, otherPathParameters []*genswagger.Parameter, otherOperation *genswagger.Operation) []Difference {
+	others := make(map[string]*genswagger.Parameter)
+	for _, p := range append(append([]*genswagger.Parameter(nil), otherPathParameters...), otherOperation.Parameters...) {
+		others[parameterKey(p)] = p
+	}
+	var differences []Difference
+	check := func(pointer []string, p *genswagger.Parameter) {
+		other, ok := others[parameterKey(p)]
+		switch {
+		case !ok:
+			differences = append(differences, Difference{
+				Pointer: jsonPointer(pointer),
+				Message: fmt.Sprintf("parameter %q in %s is missing", p.Name, p.In),
+			})
+		case p.In != "body" && parameterType(p) != parameterType(other):
+			differences = append(differences, Difference{
+				Pointer: jsonPointer(pointer),
+				Message: fmt.Sprintf("parameter %q in %s has type %s instead of %s", p.Name, p.In, parameterType(other), parameterType(p)),
+			})
+		}
+	}
+	overridden := make(map[string]bool)
+	for i, p := range operation.Parameters {
+		overridden[parameterKey(p)] = true
+		check(append(append([]string(nil), pointer...), "parameters", strconv.Itoa(i)), p)
+	}
+	for i, p := range pathParameters {
+		if !overridden[parameterKey(p)] {
+			check([]string{pointer[0], pointer[1], "parameters", strconv.Itoa(i)}, p)
+		}
+	}
+	return differences
+}
+
+// parameterKey returns the key that identifies p in an operation. There is a
+// single body parameter whose name does not matter.
+func parameterKey(p *genswagger.Parameter) string {
+	if p.In == "body" {
+		return p.In
+	}
+	return p.In + ":" + p.Name
+}
+
+// parameterType returns the type of p, including the types of the items of
+// arrays (e.g. array of integer).
+func parameterType(p *genswagger.Parameter) string {
+	t := p.Type
+	if t == "array" {
+		for items := p.Items; items != nil; items = items.Items {
+			t += " of " + items.Type
+		}
+	}
+	return t
+}
+
+// diffResponses returns the responses of operation that are missing in
+// otherOperation or whose body is dropped.
+func diffResponses(pointer []string, operation, otherOperation *genswagger.Operation) []Difference {
+	var codes []string
+	for code := range operation.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	var differences []Difference
+	for _, code := range codes {
+		pointer := append(append([]string(nil), pointer...), "responses", code)
+		other, ok := otherOperation.Responses[code]
+		switch {
+		case !ok:
+			differences = append(differences, Difference{
+				Pointer: jsonPointer(pointer),
+				Message: fmt.Sprintf("response %q is dropped", code),
+			})
+		case operation.Responses[code].Schema != nil && other.Schema == nil:
+			differences = append(differences, Difference{
+				Pointer: jsonPointer(append(pointer, "schema")),
+				Message: fmt.Sprintf("body of response %q is dropped", code),
+			})
+		}
+	}
+	return differences
+}
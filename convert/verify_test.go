@@ -0,0 +1,152 @@
+package convert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+func TestDiffSwagger(t *testing.T) {
+	original := genswagger.Swagger{
+		BasePath: "/v1",
+		Paths: map[string]interface{}{
+			"/users/{id}": map[string]interface{}{
+				"parameters": []interface{}{
+					map[string]interface{}{"name": "id", "in": "path", "type": "integer"},
+				},
+				"get": map[string]interface{}{
+					"parameters": []interface{}{
+						map[string]interface{}{"name": "fields", "in": "query", "type": "array", "items": map[string]interface{}{"type": "string"}},
+						map[string]interface{}{"name": "X-Trace", "in": "header", "type": "string"},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "OK", "schema": map[string]interface{}{"type": "object"}},
+						"404": map[string]interface{}{"description": "Not Found"},
+					},
+				},
+				"delete": map[string]interface{}{
+					"responses": map[string]interface{}{"204": map[string]interface{}{"description": "No Content"}},
+				},
+			},
+			"/groups": map[string]interface{}{
+				"get": map[string]interface{}{"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}}},
+			},
+			"/internal": map[string]interface{}{
+				"get": map[string]interface{}{"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}}},
+			},
+			"/admin": map[string]interface{}{
+				"get": map[string]interface{}{"tags": []interface{}{"admin"}},
+			},
+		},
+	}
+	regenerated := genswagger.Swagger{
+		Paths: map[string]interface{}{
+			"/v1/users/{id}": &genswagger.Path{
+				Get: &genswagger.Operation{
+					Parameters: []*genswagger.Parameter{
+						&genswagger.Parameter{Name: "id", In: "path", Type: "string"},
+						&genswagger.Parameter{Name: "fields", In: "query", Type: "array", Items: &genswagger.Items{Type: "string"}},
+					},
+					Responses: map[string]*genswagger.Response{
+						"200": &genswagger.Response{Description: "OK"},
+					},
+				},
+			},
+			"/v1/internal": &genswagger.Path{},
+		},
+	}
+	c := newConverter(original)
+	c.ignoredPaths = []string{"/internal"}
+	c.ignoredTags = []string{"admin"}
+	actual, err := c.diffSwagger(original, regenerated)
+	if err != nil {
+		t.Fatalf("diffSwagger returned %s", err)
+	}
+	expected := []Difference{
+		{Pointer: "/paths/~1groups", Message: `path "/groups" is missing`},
+		{Pointer: "/paths/~1users~1{id}/delete", Message: "operation DELETE /users/{id} is missing"},
+		{Pointer: "/paths/~1users~1{id}/get/parameters/1", Message: `parameter "X-Trace" in header is missing`},
+		{Pointer: "/paths/~1users~1{id}/parameters/0", Message: `parameter "id" in path has type string instead of integer`},
+		{Pointer: "/paths/~1users~1{id}/get/responses/200/schema", Message: `body of response "200" is dropped`},
+		{Pointer: "/paths/~1users~1{id}/get/responses/404", Message: `response "404" is dropped`},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("\ngot:\n%v\nexpected:\n%v", actual, expected)
+	}
+
+	regenerated.Paths["/v1/users/{id}"].(*genswagger.Path).Get.Parameters[1].Items.Type = "integer"
+	actual, err = c.diffSwagger(original, regenerated)
+	if err != nil {
+		t.Fatalf("diffSwagger returned %s", err)
+	}
+	if expected := "parameter \"fields\" in query has type array of integer instead of array of string"; len(actual) < 3 || actual[2].Message != expected {
+		t.Errorf("got %v, expected %s", actual, expected)
+	}
+}
+
+func TestParameterType(t *testing.T) {
+	cases := map[string]struct {
+		parameter genswagger.Parameter
+		expected  string
+	}{
+		"scalar": {genswagger.Parameter{Type: "integer"}, "integer"},
+		"array":  {genswagger.Parameter{Type: "array", Items: &genswagger.Items{Type: "string"}}, "array of string"},
+		"nested": {genswagger.Parameter{Type: "array", Items: &genswagger.Items{Type: "array", Items: &genswagger.Items{Type: "number"}}}, "array of array of number"},
+		"body":   {genswagger.Parameter{Schema: &genschema.JSONSchema{}}, ""},
+	}
+	for k, tc := range cases {
+		if actual := parameterType(&tc.parameter); actual != tc.expected {
+			t.Errorf("%s: \ngot:\n%v\nexpected:\n%v", k, actual, tc.expected)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	document := `swagger: "2.0"
+info: {title: petstore, version: "1.0"}
+basePath: /v1
+paths:
+  /pets:
+    get:
+      operationId: list
+      parameters:
+      - {name: tags, in: query, type: array, items: {type: string}}
+      - {name: X-Trace, in: header, type: string}
+      responses:
+        "200":
+          description: OK
+          schema:
+            type: array
+            items: {$ref: "#/definitions/Pet"}
+    post:
+      operationId: create
+      parameters:
+      - {name: body, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        "201": {description: Created, schema: {$ref: "#/definitions/Pet"}}
+  /pets/{id}:
+    get:
+      operationId: show
+      parameters:
+      - {name: id, in: path, required: true, type: integer}
+      responses:
+        "200": {description: OK, schema: {$ref: "#/definitions/Pet"}}
+        "404": {description: Not Found}
+definitions:
+  Pet:
+    type: object
+    properties:
+      id: {type: integer}
+      name: {type: string}
+`
+	differences, err := Verify(strings.NewReader(document), Options{})
+	if err != nil {
+		t.Fatalf("Verify returned %s", err)
+	}
+	if len(differences) != 0 {
+		t.Errorf("unexpected differences %v", differences)
+	}
+}